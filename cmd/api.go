@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/gitkraken/gk-cli/internal/api"
+	"github.com/gitkraken/gk-cli/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// apiCmd represents the api command
+var apiCmd = &cobra.Command{
+	Use:   "api <provider> <path>",
+	Short: "Make an authenticated API request",
+	Long: `Make an authenticated request to the GitHub, GitLab, Bitbucket or GitKraken
+API using the configured credentials. The path is relative to the provider's
+API base URL, e.g. /user or /repos/{owner}/{repo}/pulls. Use provider@account,
+e.g. github@work, to send the request with a named account.
+
+Fields given with -f are sent as query parameters for GET and DELETE requests
+and as a JSON body otherwise. The method defaults to GET, or POST when fields
+are given.
+
+--paginate follows Link headers and Bitbucket's next links and merges the
+pages into one array. It works for responses that are a JSON array, or an
+object listing its results in 'values' (Bitbucket) or 'items' (GitHub search).
+
+Examples:
+  gk api github /user
+  gk api github@work /user
+  gk api github /repos/acme/api/issues --paginate --filter '.[] | .title'
+  gk api github "/search/issues?q=repo:acme/api+is:pr" --paginate
+  gk api gitlab /projects/acme%2Fapi/merge_requests -f state=opened
+  gk api github -X PATCH /repos/acme/api/issues/12 -f state=closed`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		providerName, path := strings.ToLower(args[0]), args[1]
+		method, _ := cmd.Flags().GetString("method")
+		fields, _ := cmd.Flags().GetStringArray("field")
+		paginate, _ := cmd.Flags().GetBool("paginate")
+		filter, _ := cmd.Flags().GetString("filter")
+
+		params, err := parseAPIFields(fields)
+		if err != nil {
+			return err
+		}
+
+		method = strings.ToUpper(method)
+		if method == "" {
+			method = "GET"
+			if len(params) > 0 {
+				method = "POST"
+			}
+		}
+
+		var body interface{}
+		if len(params) > 0 {
+			if method == "GET" || method == "DELETE" {
+				query := url.Values{}
+				for k, v := range params {
+					query.Set(k, v)
+				}
+				sep := "?"
+				if strings.Contains(path, "?") {
+					sep = "&"
+				}
+				path += sep + query.Encode()
+			} else {
+				body = params
+			}
+		}
+
+		if paginate && method != "GET" {
+			return fmt.Errorf("--paginate is only supported for GET requests")
+		}
+
+		factory := newProviderFactory()
+		providerName, account := api.SplitAccountKey(providerName)
+		if account != "" {
+			factory = factory.Account(api.AccountKey(providerName, account))
+		}
+		client, err := factory.RawClient(providerName)
+		if err != nil {
+			return err
+		}
+
+		data, err := api.RawRequest(context.Background(), client, method, path, body, paginate)
+		if err != nil {
+			return err
+		}
+
+		return printAPIResponse(cmd, data, filter)
+	},
+}
+
+// parseAPIFields parses key=value pairs given with -f
+func parseAPIFields(fields []string) (map[string]string, error) {
+	params := make(map[string]string)
+	for _, field := range fields {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid field '%s' (expected key=value)", field)
+		}
+		params[key] = value
+	}
+	return params, nil
+}
+
+// printAPIResponse prints a response body, pretty-printing JSON and
+// applying the filter expression when given
+func printAPIResponse(cmd *cobra.Command, data []byte, filter string) error {
+	out := cmd.OutOrStdout()
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		if filter != "" {
+			return fmt.Errorf("cannot filter non-JSON response")
+		}
+		_, err := out.Write(data)
+		return err
+	}
+
+	results := []interface{}{decoded}
+	if filter != "" {
+		var err error
+		if results, err = utils.FilterJSON(filter, decoded); err != nil {
+			return err
+		}
+	}
+
+	for _, result := range results {
+		if s, ok := result.(string); ok && filter != "" {
+			fmt.Fprintln(out, s)
+			continue
+		}
+		formatted, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to format response: %w", err)
+		}
+		fmt.Fprintln(out, string(formatted))
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(apiCmd)
+
+	apiCmd.Flags().StringP("method", "X", "", "HTTP method (default: GET, or POST with fields)")
+	apiCmd.Flags().StringArrayP("field", "f", nil, "Add a key=value parameter (repeatable)")
+	apiCmd.Flags().Bool("paginate", false, "Fetch all pages and merge the results into one array")
+	apiCmd.Flags().StringP("filter", "q", "", "Filter JSON output with a jq-like expression")
+}
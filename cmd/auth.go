@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gitkraken/gk-cli/internal/api"
+	"github.com/gitkraken/gk-cli/internal/auth"
+	"github.com/gitkraken/gk-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// authCmd represents the auth command
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Inspect GitKraken authentication",
+	Long:  `Inspect the GitKraken account gk is logged in with. Use 'gk login' and 'gk logout' to change it.`,
+}
+
+// authStatusCmd represents the auth status command
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the logged in account, token expiry and scopes",
+	Long: `Show the GitKraken account gk is logged in with, when the access token
+expires and which scopes it was granted. An expired token is refreshed first.
+Exits with an error when not logged in or the session can't be refreshed.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if config.Get().Auth.Token == "" {
+			return fmt.Errorf("not logged in. Run 'gk login'")
+		}
+
+		if _, err := auth.GetToken(); err != nil {
+			fmt.Printf("✗ %v\n", err)
+			return fmt.Errorf("session is no longer valid. Run 'gk login'")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		client, err := api.NewClient("")
+		if err == nil {
+			var user *api.User
+			if user, err = client.GetCurrentUser(ctx); err == nil {
+				account := user.Username
+				if user.Email != "" {
+					account += " (" + user.Email + ")"
+				}
+				fmt.Printf("✓ Logged in as %s\n", account)
+			}
+		}
+		if err != nil {
+			fmt.Println("✓ Logged in")
+			fmt.Printf("⚠ Could not fetch account details: %v\n", err)
+		}
+
+		token := auth.StoredToken()
+		if token.Expiry.IsZero() {
+			fmt.Println("  Expires: never")
+		} else {
+			fmt.Printf("  Expires: %s (in %s)\n", token.Expiry.Local().Format(time.RFC1123), time.Until(token.Expiry).Round(time.Minute))
+		}
+
+		if scopes := auth.StoredScopes(); len(scopes) > 0 {
+			fmt.Printf("  Scopes:  %s\n", strings.Join(scopes, ", "))
+		} else {
+			fmt.Println("  Scopes:  not reported")
+		}
+
+		if token.RefreshToken != "" {
+			fmt.Println("  Refresh: automatic")
+		} else {
+			fmt.Println("  Refresh: not available, run 'gk login' when the token expires")
+		}
+		fmt.Printf("  Stored:  %s\n", config.SecretStoreName())
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(authCmd)
+	authCmd.AddCommand(authStatusCmd)
+}
@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/gitkraken/gk-cli/internal/config"
+	"github.com/gitkraken/gk-cli/internal/filelock"
+	"github.com/gitkraken/gk-cli/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Get, set and validate configuration values",
+	Long: `Get, set and validate gk configuration values. Keys are dotted paths into
+the configuration file, e.g. theme or providers.github.username.
+
+Examples:
+  gk config get theme
+  gk config set theme dark
+  gk config unset providers.gitlab
+  gk config edit`,
+}
+
+// configGetCmd represents the config get command
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a configuration value",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		value, err := config.GetValue(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Println(value)
+		return nil
+	},
+}
+
+// configSetCmd represents the config set command
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a configuration value",
+	Long: `Set a configuration value. The value is parsed according to the key's type
+and the change is rejected if it makes the configuration invalid.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := config.SetValue(args[0], args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Set %s\n", args[0])
+		if origin := config.Origin(args[0]); origin != "file:"+config.Path() {
+			fmt.Printf("⚠ %s is overridden by %s\n", args[0], origin)
+		}
+		return nil
+	},
+}
+
+// configUnsetCmd represents the config unset command
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a configuration value or section entry",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := config.UnsetValue(args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Unset %s\n", args[0])
+		return nil
+	},
+}
+
+// configListCmd represents the config list command
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all configuration values",
+	Long: `List all configuration values as dotted keys. Secrets are masked.
+
+With --show-origin, each value is prefixed with where it came from: the
+global config file, a repository's .gk.yaml or a GK_ environment variable.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		showOrigin, _ := cmd.Flags().GetBool("show-origin")
+		for _, s := range config.List() {
+			if showOrigin {
+				fmt.Printf("%s\t", s.Origin)
+			}
+			fmt.Printf("%s=%s\n", s.Key, s.Value)
+		}
+		return nil
+	},
+}
+
+// configEditCmd represents the config edit command
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit the configuration file in $EDITOR",
+	Long: `Open a copy of the configuration file in $VISUAL or $EDITOR. The real file
+is only replaced once the edited copy passes validation.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return editConfig(config.Path())
+	},
+}
+
+// editConfig lets the user edit a temporary copy of the config file until
+// it is valid or they give up
+func editConfig(path string) error {
+	original, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		// Nothing saved yet; start from an empty config
+		original, err = []byte(fmt.Sprintf("version: %d\n", config.CurrentVersion)), nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "config-*.yaml")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(original)
+	tmp.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	for {
+		if err := runEditor(tmp.Name()); err != nil {
+			return err
+		}
+		edited, err := os.ReadFile(tmp.Name())
+		if err != nil {
+			return fmt.Errorf("failed to read edited config: %w", err)
+		}
+		if bytes.Equal(edited, original) {
+			fmt.Println("No changes made")
+			return nil
+		}
+
+		issues, err := config.Validate(edited)
+		if err == nil && len(issues) == 0 {
+			if err := filelock.WriteFile(path, edited, 0600); err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+			fmt.Printf("✓ Saved %s\n", path)
+			return nil
+		}
+
+		if err != nil {
+			fmt.Printf("✗ %v\n", err)
+		}
+		for _, issue := range issues {
+			fmt.Printf("✗ %s\n", issue)
+		}
+		answer, err := utils.PromptString("Edit again? [Y/n] ")
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(strings.ToLower(answer), "n") {
+			return fmt.Errorf("changes discarded; %s was not modified", path)
+		}
+	}
+}
+
+// runEditor opens a file in the user's editor and waits for it to close
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+
+	// The editor may include arguments, e.g. "code --wait"
+	parts := strings.Fields(editor)
+	c := exec.Command(parts[0], append(parts[1:], path)...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("editor failed: %w", err)
+	}
+	return nil
+}
+
+// configValidateCmd represents the config validate command
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [file]",
+	Short: "Check the configuration file for mistakes",
+	Long: `Check a configuration file (default: the current one) for unknown keys,
+values of the wrong type and missing required fields, with line numbers.
+Files named .gk.yaml are checked against the repository schema.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := config.Path()
+		if len(args) > 0 {
+			path = args[0]
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read config: %w", err)
+		}
+
+		validate := config.Validate
+		if filepath.Base(path) == config.RepoConfigName {
+			validate = config.ValidateRepo
+		}
+		issues, err := validate(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if len(issues) == 0 {
+			fmt.Printf("✓ %s is valid\n", path)
+			return nil
+		}
+
+		fmt.Println(path)
+		for _, issue := range issues {
+			fmt.Printf("  ✗ %s\n", issue)
+		}
+		return fmt.Errorf("%d problem(s) found in %s", len(issues), path)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configEditCmd)
+	configCmd.AddCommand(configValidateCmd)
+
+	configListCmd.Flags().Bool("show-origin", false, "Show where each value came from")
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/gitkraken/gk-cli/internal/api"
+	"github.com/gitkraken/gk-cli/internal/inbox"
+	"github.com/gitkraken/gk-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+// inboxCmd represents the inbox command
+var inboxCmd = &cobra.Command{
+	Use:   "inbox",
+	Short: "Show notifications from all providers",
+	Long: `Show a combined inbox of GitHub notifications, GitLab to-dos and Bitbucket
+pull request activity. Bitbucket review requests are found by scanning the
+Bitbucket repositories in your workspaces.
+
+Use 'gk inbox read <id>' or 'gk inbox done <id>' to triage items.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		all, _ := cmd.Flags().GetBool("all")
+		reasons, _ := cmd.Flags().GetStringSlice("reason")
+		providers, _ := cmd.Flags().GetStringSlice("provider")
+
+		factory := newProviderFactory()
+		notifications, errs := inbox.Load(factory, inbox.Options{
+			All:       all,
+			Reasons:   reasons,
+			Providers: providers,
+			Repos:     workspaceReposByProvider(),
+		})
+
+		for name, err := range errs {
+			fmt.Printf("⚠ Skipping %s: %v\n", name, err)
+		}
+
+		if len(notifications) == 0 {
+			fmt.Println("Inbox zero 🎉")
+			return nil
+		}
+
+		fmt.Println("📥 Inbox")
+		for _, n := range notifications {
+			marker := "●"
+			if !n.Unread {
+				marker = " "
+			}
+			icon := "🔔"
+			switch n.Type {
+			case "pr":
+				icon = "🔵"
+			case "issue":
+				icon = "⚪"
+			}
+			fmt.Printf("%s %s %s [%s] %s\n", marker, icon, n.Title, n.Reason, n.Repo)
+			fmt.Printf("     %s | %s\n", n.Key(), n.URL)
+		}
+		fmt.Printf("\nTotal: %d notification(s)\n", len(notifications))
+		return nil
+	},
+}
+
+// inboxReadCmd represents the inbox read command
+var inboxReadCmd = &cobra.Command{
+	Use:   "read <id>...",
+	Short: "Mark notifications as read",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		factory := newProviderFactory()
+		for _, key := range args {
+			if err := inbox.MarkRead(factory, key); err != nil {
+				return fmt.Errorf("failed to mark %s as read: %w", key, err)
+			}
+			fmt.Printf("✓ Marked %s as read\n", key)
+		}
+		return nil
+	},
+}
+
+// inboxDoneCmd represents the inbox done command
+var inboxDoneCmd = &cobra.Command{
+	Use:   "done <id>...",
+	Short: "Mark notifications as done",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		factory := newProviderFactory()
+		for _, key := range args {
+			if err := inbox.MarkDone(factory, key); err != nil {
+				return fmt.Errorf("failed to mark %s as done: %w", key, err)
+			}
+			fmt.Printf("✓ Marked %s as done\n", key)
+		}
+		return nil
+	},
+}
+
+// workspaceReposByProvider collects the repositories of every workspace,
+// grouped by provider, without prompting for a workspace
+func workspaceReposByProvider() map[string][]api.RepoRef {
+	repos := make(map[string][]api.RepoRef)
+	seen := make(map[string]bool)
+
+	names, err := workspace.List()
+	if err != nil {
+		return repos
+	}
+	for _, name := range names {
+		ws, err := workspace.Load(name)
+		if err != nil {
+			continue
+		}
+		for _, repo := range ws.Repos {
+			providerName, owner, repoName, err := api.ParseRepoURL(repo.Remote)
+			if err != nil {
+				continue
+			}
+			ref := api.RepoRef{Owner: owner, Name: repoName}
+			if seen[providerName+":"+ref.String()] {
+				continue
+			}
+			seen[providerName+":"+ref.String()] = true
+			repos[providerName] = append(repos[providerName], ref)
+		}
+	}
+	return repos
+}
+
+func init() {
+	rootCmd.AddCommand(inboxCmd)
+	inboxCmd.AddCommand(inboxReadCmd)
+	inboxCmd.AddCommand(inboxDoneCmd)
+
+	inboxCmd.Flags().BoolP("all", "a", false, "Include read and done notifications")
+	inboxCmd.Flags().StringSlice("reason", nil, "Filter by reason (review_requested, mentioned, assigned, author, comment, other)")
+	inboxCmd.Flags().StringSlice("provider", nil, "Providers to include (default: all configured)")
+}
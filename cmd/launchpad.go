@@ -25,7 +25,7 @@ Shortcuts:
 		}
 
 		fmt.Println("Loading Launchpad...")
-		lp, err := launchpad.LoadItems(ws)
+		lp, err := launchpad.LoadItems(newProviderFactory(), ws)
 		if err != nil {
 			return fmt.Errorf("failed to load launchpad: %w", err)
 		}
@@ -37,4 +37,5 @@ Shortcuts:
 
 func init() {
 	rootCmd.AddCommand(launchpadCmd)
+	launchpadCmd.Flags().StringVarP(&workspaceName, "workspace", "w", "", "workspace name")
 }
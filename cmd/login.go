@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/gitkraken/gk-cli/internal/auth"
 	"github.com/gitkraken/gk-cli/internal/config"
@@ -14,7 +16,13 @@ var loginCmd = &cobra.Command{
 	Use:   "login",
 	Short: "Authenticate with GitKraken",
 	Long: `Login to GitKraken to enable cloud features and workspace synchronization.
-This will open a browser window for authentication.`,
+This will open a browser window for authentication. On remote machines and
+in containers, use --device to authorize from a browser on another device.
+
+The OAuth client can be changed with the oauth.client_id, oauth.auth_url,
+oauth.token_url, oauth.device_auth_url and oauth.revoke_url config keys, or
+the GITKRAKEN_CLIENT_ID, GITKRAKEN_AUTH_URL, GITKRAKEN_TOKEN_URL,
+GITKRAKEN_DEVICE_AUTH_URL and GITKRAKEN_REVOKE_URL environment variables.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Check if already authenticated
 		if config.IsAuthenticated() {
@@ -22,25 +30,15 @@ This will open a browser window for authentication.`,
 			return nil
 		}
 
-		// TODO: Get actual client ID and secret from environment or config
-		// For now, these need to be provided by GitKraken
-		clientID := os.Getenv("GITKRAKEN_CLIENT_ID")
-		clientSecret := os.Getenv("GITKRAKEN_CLIENT_SECRET")
-		
-		if clientID == "" || clientSecret == "" {
-			fmt.Println("⚠ OAuth credentials not configured.")
-			fmt.Println("Set GITKRAKEN_CLIENT_ID and GITKRAKEN_CLIENT_SECRET environment variables.")
-			fmt.Println("\nFor now, authentication is not fully implemented.")
-			fmt.Println("Once GitKraken OAuth credentials are available, this will:")
-			fmt.Println("  1. Open your browser for authentication")
-			fmt.Println("  2. Handle the callback on port 1314")
-			fmt.Println("  3. Store your authentication tokens")
+		auth.InitOAuth(auth.LoadSettings())
+
+		if device, _ := cmd.Flags().GetBool("device"); device {
+			if err := auth.StartDeviceFlow(cmd.OutOrStdout()); err != nil {
+				return fmt.Errorf("authentication failed: %w", err)
+			}
 			return nil
 		}
 
-		// Initialize OAuth
-		auth.InitOAuth(clientID, clientSecret, "http://localhost:1314/callback")
-
 		fmt.Println("Opening browser for authentication...")
 		if err := auth.StartAuthFlow(); err != nil {
 			return fmt.Errorf("authentication failed: %w", err)
@@ -54,17 +52,31 @@ This will open a browser window for authentication.`,
 var logoutCmd = &cobra.Command{
 	Use:   "logout",
 	Short: "Logout from GitKraken",
-	Long:  `Logout from GitKraken and clear stored authentication tokens.`,
+	Long: `Logout from GitKraken, revoke the stored tokens on the server and clear
+them locally. The local tokens are cleared even if revocation fails.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if config.Saved().Auth.Token != "" {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			if err := auth.Revoke(ctx); err != nil {
+				fmt.Fprintf(os.Stderr, "⚠ Could not revoke tokens: %v\n", err)
+			}
+		}
+
 		if err := config.ClearAuth(); err != nil {
 			fmt.Fprintf(os.Stderr, "Error clearing authentication: %v\n", err)
 			return
 		}
 		fmt.Println("Successfully logged out")
+		if os.Getenv("GK_AUTH_TOKEN") != "" {
+			fmt.Fprintln(os.Stderr, "⚠ GK_AUTH_TOKEN is set and is still used; it was not revoked")
+		}
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(loginCmd)
 	rootCmd.AddCommand(logoutCmd)
+
+	loginCmd.Flags().Bool("device", false, "Authorize with a code on another device instead of opening a browser")
 }
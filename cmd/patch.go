@@ -3,8 +3,6 @@ package cmd
 import (
 	"context"
 	"fmt"
-	"os"
-	"strings"
 
 	"github.com/gitkraken/gk-cli/internal/api"
 	"github.com/gitkraken/gk-cli/internal/config"
@@ -7,7 +7,6 @@ import (
 	"strings"
 
 	"github.com/gitkraken/gk-cli/internal/api"
-	"github.com/gitkraken/gk-cli/internal/config"
 	"github.com/gitkraken/gk-cli/internal/workspace"
 	"github.com/spf13/cobra"
 )
@@ -37,56 +36,32 @@ Filters can be applied to show specific states or repositories.`,
 			state = "open"
 		}
 
-		factory := api.NewProviderFactory()
-		cfg := config.Get()
-
-		// Setup providers from config
-		if providers, ok := cfg.Providers["github"].(map[string]interface{}); ok {
-			if token, ok := providers["token"].(string); ok {
-				factory.SetGitHubToken(token)
-			}
-		}
-		if providers, ok := cfg.Providers["gitlab"].(map[string]interface{}); ok {
-			if token, ok := providers["token"].(string); ok {
-				factory.SetGitLabToken(token)
-			}
-		}
-		if providers, ok := cfg.Providers["bitbucket"].(map[string]interface{}); ok {
-			if user, ok := providers["username"].(string); ok {
-				if pass, ok := providers["password"].(string); ok {
-					factory.SetBitbucketCreds(user, pass)
-				}
-			}
-		}
+		factory := newProviderFactory()
 
 		fmt.Printf("Listing pull requests (%s) for workspace '%s'...\n\n", state, ws.Name)
 
-		totalPRs := 0
+		var repos []workspace.Repo
+		var remotes []string
 		for _, repo := range ws.Repos {
 			if repo.Remote == "" {
 				continue
 			}
+			repos = append(repos, repo)
+			remotes = append(remotes, repo.Remote)
+		}
 
-			providerName, owner, repoName, err := api.ParseRepoURL(repo.Remote)
-			if err != nil {
-				fmt.Printf("⚠ Skipping %s: %v\n", repo.Name, err)
-				continue
-			}
-
-			provider, err := factory.GetProvider(providerName)
-			if err != nil {
-				fmt.Printf("⚠ Skipping %s: %v\n", repo.Name, err)
-				continue
-			}
+		results := factory.FetchRemotes(remotes, api.BatchOptions{State: state, PullRequests: true})
 
-			prs, err := provider.ListPullRequests(owner, repoName, state)
-			if err != nil {
-				fmt.Printf("⚠ Error fetching PRs for %s: %v\n", repo.Name, err)
+		totalPRs := 0
+		for i, result := range results {
+			if result.Items.Err != nil {
+				fmt.Printf("⚠ Skipping %s: %v\n", repos[i].Name, result.Items.Err)
 				continue
 			}
 
+			prs := result.Items.PullRequests
 			if len(prs) > 0 {
-				fmt.Printf("📦 %s/%s (%s):\n", owner, repoName, providerName)
+				fmt.Printf("📦 %s (%s):\n", result.Repo, result.Provider)
 				for _, pr := range prs {
 					status := "🟢"
 					if pr.State != "open" {
@@ -94,6 +69,9 @@ Filters can be applied to show specific states or repositories.`,
 					}
 					fmt.Printf("  %s #%d: %s [%s → %s]\n", status, pr.Number, pr.Title, pr.SourceBranch, pr.TargetBranch)
 					fmt.Printf("     Author: %s | %s\n", pr.Author, pr.URL)
+					if pr.ReviewState != "" || pr.CheckStatus != "" {
+						fmt.Printf("     Review: %s | Checks: %s\n", valueOrDash(pr.ReviewState), valueOrDash(pr.CheckStatus))
+					}
 				}
 				fmt.Println()
 				totalPRs += len(prs)
@@ -110,6 +88,14 @@ Filters can be applied to show specific states or repositories.`,
 	},
 }
 
+// valueOrDash returns s, or "-" when s is empty
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
 // prViewCmd represents the pr view command
 var prViewCmd = &cobra.Command{
 	Use:   "view [pr-number]",
@@ -145,33 +131,7 @@ Otherwise, it will list all PRs for selection.`,
 			return fmt.Errorf("failed to parse repository URL: %w", err)
 		}
 
-		factory := api.NewProviderFactory()
-		cfg := config.Get()
-
-		// Setup provider
-		switch providerName {
-		case "github":
-			if providers, ok := cfg.Providers["github"].(map[string]interface{}); ok {
-				if token, ok := providers["token"].(string); ok {
-					factory.SetGitHubToken(token)
-				}
-			}
-		case "gitlab":
-			if providers, ok := cfg.Providers["gitlab"].(map[string]interface{}); ok {
-				if token, ok := providers["token"].(string); ok {
-					factory.SetGitLabToken(token)
-				}
-			}
-		case "bitbucket":
-			if providers, ok := cfg.Providers["bitbucket"].(map[string]interface{}); ok {
-				if user, ok := providers["username"].(string); ok {
-					if pass, ok := providers["password"].(string); ok {
-						factory.SetBitbucketCreds(user, pass)
-					}
-				}
-			}
-		}
-
+		factory := newProviderFactory().ForRemote(repo.Remote)
 		provider, err := factory.GetProvider(providerName)
 		if err != nil {
 			return fmt.Errorf("provider not configured: %w", err)
@@ -246,4 +206,5 @@ func init() {
 	prCmd.AddCommand(prSuggestCmd)
 
 	prListCmd.Flags().StringP("state", "s", "open", "Filter by state (open, closed, all)")
+	prCmd.PersistentFlags().StringVarP(&workspaceName, "workspace", "w", "", "workspace name")
 }
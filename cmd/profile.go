@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/gitkraken/gk-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// profileCmd represents the profile command
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage configuration profiles",
+	Long: `Manage configuration profiles. Each profile has its own GitKraken login,
+providers and workspaces, e.g. one per client.
+
+The active profile is chosen by --profile, then GK_PROFILE, then
+'gk profile use'. Without any of them the default profile is used.
+
+Examples:
+  gk profile create acme
+  gk --profile acme login
+  gk profile use acme`,
+}
+
+// profileListCmd represents the profile list command
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List profiles",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		profiles, err := config.Profiles()
+		if err != nil {
+			return err
+		}
+		for _, name := range profiles {
+			if name == config.Profile() {
+				fmt.Printf("* %s\n", name)
+			} else {
+				fmt.Printf("  %s\n", name)
+			}
+		}
+		return nil
+	},
+}
+
+// profileUseCmd represents the profile use command
+var profileUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Make a profile the default",
+	Long:  `Make a profile the one used when neither --profile nor GK_PROFILE is given.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := config.UseProfile(args[0]); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Switched to profile %s\n", args[0])
+		return nil
+	},
+}
+
+// profileCreateCmd represents the profile create command
+var profileCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create an empty profile",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name := args[0]
+		if err := config.CreateProfile(name); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Created profile %s\n", name)
+
+		if use, _ := cmd.Flags().GetBool("use"); use {
+			if err := config.UseProfile(name); err != nil {
+				return err
+			}
+			fmt.Printf("✓ Switched to profile %s\n", name)
+		} else {
+			fmt.Printf("Run 'gk --profile %s login' to sign in, or 'gk profile use %s' to switch to it.\n", name, name)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(profileCmd)
+	profileCmd.AddCommand(profileListCmd)
+	profileCmd.AddCommand(profileUseCmd)
+	profileCmd.AddCommand(profileCreateCmd)
+
+	profileCreateCmd.Flags().Bool("use", false, "Switch to the new profile")
+}
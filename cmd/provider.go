@@ -3,9 +3,14 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/gitkraken/gk-cli/internal/api"
+	"github.com/gitkraken/gk-cli/internal/auth"
 	"github.com/gitkraken/gk-cli/internal/config"
+	"github.com/gitkraken/gk-cli/internal/credentials"
 	"github.com/gitkraken/gk-cli/pkg/utils"
 	"github.com/spf13/cobra"
 )
@@ -23,79 +28,205 @@ var providerAddCmd = &cobra.Command{
 	Use:   "add [provider]",
 	Short: "Add a provider connection",
 	Long: `Add a provider connection (GitHub, GitLab, Bitbucket, etc.). 
-For GitHub and GitLab, you can provide a personal access token.
-For Bitbucket, provide username and app password.`,
+For GitHub and GitLab, you can provide a personal access token, or use --oauth
+to authorize in the browser with a one-time code. OAuth tokens are refreshed
+automatically when they expire. --oauth needs the client ID of an OAuth app
+registered on the provider with the device flow enabled, set in
+GK_GITHUB_CLIENT_ID or GK_GITLAB_CLIENT_ID.
+For Bitbucket, provide username and app password.
+
+Use --from to import a token from gh, glab, a git credential helper or the
+GITHUB_TOKEN/GITLAB_TOKEN environment variables. With --dynamic the token is
+not copied: it is read from the source each time gk runs.
+
+Use --account (or provider@account) to add further named accounts, and
+'gk provider route add' to choose which repositories use them.`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		providerName := strings.ToLower(args[0])
-		cfg := config.Get()
+		providerName, account := api.SplitAccountKey(strings.ToLower(args[0]))
+		if flagAccount, _ := cmd.Flags().GetString("account"); flagAccount != "" {
+			account = flagAccount
+		}
+		key := api.AccountKey(providerName, account)
 
-		if cfg.Providers == nil {
-			cfg.Providers = make(map[string]interface{})
+		source, _ := cmd.Flags().GetString("from")
+		dynamic, _ := cmd.Flags().GetBool("dynamic")
+		if dynamic && source == "" {
+			return fmt.Errorf("--dynamic requires --from")
 		}
 
-		switch providerName {
-		case "github":
-			token, _ := cmd.Flags().GetString("token")
-			if token == "" {
-				var err error
-				token, err = utils.PromptString("GitHub Personal Access Token: ")
-				if err != nil {
-					return err
-				}
+		var settings config.ProviderConfig
+		if source != "" {
+			token, err := credentials.ExternalToken(source, providerName)
+			if err != nil {
+				return fmt.Errorf("failed to import %s token from %s: %w", providerName, source, err)
 			}
-			cfg.Providers["github"] = map[string]interface{}{
-				"token": token,
+			if dynamic {
+				settings = config.ProviderConfig{Auth: config.ProviderAuthDynamic, Source: source}
+			} else {
+				settings = config.ProviderConfig{Token: token}
 			}
-			fmt.Println("✓ GitHub provider added")
-
-		case "gitlab":
-			token, _ := cmd.Flags().GetString("token")
-			if token == "" {
-				var err error
-				token, err = utils.PromptString("GitLab Personal Access Token: ")
-				if err != nil {
-					return err
-				}
-			}
-			cfg.Providers["gitlab"] = map[string]interface{}{
-				"token": token,
+		} else if useOAuth, _ := cmd.Flags().GetBool("oauth"); useOAuth {
+			var err error
+			settings, err = auth.ProviderDeviceLogin(providerName, cmd.OutOrStdout())
+			if err != nil {
+				return fmt.Errorf("failed to authorize %s: %w", providerName, err)
 			}
-			fmt.Println("✓ GitLab provider added")
-
-		case "bitbucket":
-			username, _ := cmd.Flags().GetString("username")
-			password, _ := cmd.Flags().GetString("password")
-			if username == "" {
-				var err error
-				username, err = utils.PromptString("Bitbucket Username: ")
-				if err != nil {
-					return err
+		} else {
+			switch providerName {
+			case "github", "gitlab":
+				token, _ := cmd.Flags().GetString("token")
+				if token == "" {
+					var err error
+					label := map[string]string{"github": "GitHub", "gitlab": "GitLab"}[providerName]
+					token, err = utils.PromptString(label + " Personal Access Token: ")
+					if err != nil {
+						return err
+					}
 				}
-			}
-			if password == "" {
-				var err error
-				password, err = utils.PromptString("Bitbucket App Password: ")
-				if err != nil {
-					return err
+				settings = config.ProviderConfig{Token: token}
+
+			case "bitbucket":
+				username, _ := cmd.Flags().GetString("username")
+				password, _ := cmd.Flags().GetString("password")
+				if username == "" {
+					var err error
+					username, err = utils.PromptString("Bitbucket Username: ")
+					if err != nil {
+						return err
+					}
+				}
+				if password == "" {
+					var err error
+					password, err = utils.PromptString("Bitbucket App Password: ")
+					if err != nil {
+						return err
+					}
 				}
+				settings = config.ProviderConfig{Username: username, Password: password}
+
+			default:
+				return fmt.Errorf("unsupported provider: %s (supported: github, gitlab, bitbucket)", providerName)
 			}
-			cfg.Providers["bitbucket"] = map[string]interface{}{
-				"username": username,
-				"password": password,
+		}
+
+		if skip, _ := cmd.Flags().GetBool("no-validate"); !skip {
+			factory := api.NewProviderFactory()
+			applyProviderSettings(factory, key, settings)
+			id, err := validateProvider(factory, providerName)
+			if err != nil {
+				return fmt.Errorf("%s credentials are not valid (use --no-validate to save anyway): %w", key, err)
 			}
-			fmt.Println("✓ Bitbucket provider added")
+			printIdentity(id, settings.ExpiresAt)
+		}
+
+		if err := config.SetProvider(key, settings); err != nil {
+			return fmt.Errorf("failed to save provider: %w", err)
+		}
+		if account != "" {
+			fmt.Printf("✓ %s provider added (account: %s)\n", strings.Title(providerName), account)
+		} else {
+			fmt.Printf("✓ %s provider added\n", strings.Title(providerName))
+		}
+		return nil
+	},
+}
 
-		default:
-			return fmt.Errorf("unsupported provider: %s (supported: github, gitlab, bitbucket)", providerName)
+// providerStatusCmd represents the provider status command
+var providerStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Check configured provider connections",
+	Long: `Re-check the credentials of every configured provider, showing the account,
+scopes and expiry, and flag scopes missing for pull requests, issues and checks.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.Get()
+		if len(cfg.Providers) == 0 {
+			fmt.Println("No providers configured.")
+			fmt.Println("Add a provider with: gk provider add <github|gitlab|bitbucket>")
+			return nil
 		}
 
-		// Update global config
-		config.UpdateProviders(cfg.Providers)
+		failed := 0
+		for _, key := range sortedKeys(cfg.Providers) {
+			settings := cfg.Providers[key]
+			providerName, _ := api.SplitAccountKey(key)
+			fmt.Println(key)
+
+			factory := api.NewProviderFactory()
+			applyProviderSettings(factory, key, settings)
+			id, err := validateProvider(factory, providerName)
+			if err != nil {
+				fmt.Printf("  ✗ %v\n", err)
+				failed++
+				continue
+			}
+			if printIdentity(id, settings.ExpiresAt) {
+				failed++
+			}
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d provider(s) need attention", failed)
+		}
 		return nil
 	},
 }
 
+// validateProvider checks a provider's credentials against its API
+func validateProvider(factory *api.ProviderFactory, name string) (*api.Identity, error) {
+	provider, err := factory.GetProvider(name)
+	if err != nil {
+		return nil, err
+	}
+	validator, ok := provider.(api.Validator)
+	if !ok {
+		return nil, fmt.Errorf("validation is not supported for %s", name)
+	}
+	return validator.Validate()
+}
+
+// printIdentity prints a validated identity and reports whether it needs
+// attention because of missing scopes or an expired token. expiresAt is
+// the stored OAuth expiry, used when the provider reports none.
+func printIdentity(id *api.Identity, expiresAt string) bool {
+	attention := false
+
+	if id.Name != "" && id.Name != id.Login {
+		fmt.Printf("  ✓ Authenticated as %s (%s)\n", id.Login, id.Name)
+	} else {
+		fmt.Printf("  ✓ Authenticated as %s\n", id.Login)
+	}
+
+	if id.Scopes != nil {
+		scopes := strings.Join(id.Scopes, ", ")
+		if scopes == "" {
+			scopes = "none"
+		}
+		fmt.Printf("  Scopes:  %s\n", scopes)
+	} else {
+		fmt.Println("  Scopes:  not reported")
+	}
+
+	if id.ExpiresAt != "" {
+		expiresAt = id.ExpiresAt
+	}
+	if expiresAt != "" {
+		fmt.Printf("  Expires: %s\n", expiresAt)
+		if t, err := time.Parse(time.RFC3339, expiresAt); err == nil && time.Now().After(t) {
+			fmt.Println("  ⚠ Token has expired")
+			attention = true
+		}
+	} else {
+		fmt.Println("  Expires: never")
+	}
+
+	if missing := api.MissingScopes(id.Provider, id.Scopes); len(missing) > 0 {
+		fmt.Printf("  ⚠ Missing scopes for pull requests, issues and checks: %s\n", strings.Join(missing, ", "))
+		attention = true
+	}
+	return attention
+}
+
 // providerListCmd represents the provider list command
 var providerListCmd = &cobra.Command{
 	Use:   "list",
@@ -110,53 +241,132 @@ var providerListCmd = &cobra.Command{
 		}
 
 		fmt.Println("Configured providers:")
-		for name, provider := range cfg.Providers {
-			if providerMap, ok := provider.(map[string]interface{}); ok {
-				fmt.Printf("  • %s", strings.Title(name))
-				if name == "bitbucket" {
-					if user, ok := providerMap["username"].(string); ok {
-						fmt.Printf(" (user: %s)", user)
-					}
-				} else {
-					if token, ok := providerMap["token"].(string); ok && len(token) > 0 {
-						fmt.Printf(" (token: %s...)", token[:min(8, len(token))])
-					}
+		for _, key := range sortedKeys(cfg.Providers) {
+			settings := cfg.Providers[key]
+			name, account := api.SplitAccountKey(key)
+			fmt.Printf("  • %s", strings.Title(name))
+			if account != "" {
+				fmt.Printf(" [%s]", account)
+			}
+			if name == "bitbucket" {
+				if settings.Username != "" {
+					fmt.Printf(" (user: %s)", settings.Username)
+				}
+			} else if settings.Auth == config.ProviderAuthDynamic {
+				fmt.Printf(" (token read from %s when used)", settings.Source)
+			} else if settings.Token != "" {
+				kind := "token"
+				if settings.Auth == config.ProviderAuthOAuth {
+					kind = "OAuth token"
 				}
-				fmt.Println()
+				fmt.Printf(" (%s stored in %s)", kind, config.SecretStoreName())
+			}
+			fmt.Println()
+		}
+
+		if len(cfg.Routes) > 0 {
+			fmt.Println("\nRoutes:")
+			for _, r := range cfg.Routes {
+				fmt.Printf("  %s → %s\n", r.Match, r.Account)
 			}
 		}
 		return nil
 	},
 }
 
-func min(a, b int) int {
-	if a < b {
-		return a
+// sortedKeys returns the provider keys in order
+func sortedKeys(m map[string]config.ProviderConfig) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// newProviderFactory returns a provider factory with credentials from config
+func newProviderFactory() *api.ProviderFactory {
+	factory := api.NewProviderFactory()
+	cfg := config.Get()
+
+	for key, settings := range cfg.Providers {
+		target := factory
+		if _, account := api.SplitAccountKey(key); account != "" {
+			target = factory.Account(key)
+		}
+		applyProviderSettings(target, key, settings)
+	}
+
+	routes := make([]api.Route, len(cfg.Routes))
+	for i, r := range cfg.Routes {
+		routes[i] = api.Route{Match: r.Match, Account: r.Account}
+	}
+	// The default account, e.g. from a repository's .gk.yaml, applies to
+	// every repository no route matched
+	if cfg.Account != "" {
+		if _, ok := cfg.Providers[cfg.Account]; ok {
+			routes = append(routes, api.Route{Match: "*", Account: cfg.Account})
+		} else {
+			fmt.Fprintf(os.Stderr, "⚠ Ignoring account %s: no such provider account. Run 'gk provider list'\n", cfg.Account)
+		}
+	}
+	factory.SetRoutes(routes)
+
+	return factory
+}
+
+// applyProviderSettings sets the credentials of a provider account (github
+// or github@work) on a factory
+func applyProviderSettings(factory *api.ProviderFactory, key string, settings config.ProviderConfig) {
+	name, _ := api.SplitAccountKey(key)
+	switch name {
+	case "github":
+		factory.SetGitHubToken(settings.Token)
+	case "gitlab":
+		factory.SetGitLabToken(settings.Token)
+	case "bitbucket":
+		factory.SetBitbucketCreds(settings.Username, settings.Password)
+	}
+
+	// OAuth tokens expire; refresh them when a client is created
+	if settings.RefreshToken != "" && (name == "github" || name == "gitlab") {
+		factory.SetTokenFunc(name, func() (string, error) { return auth.ProviderToken(key) })
+	}
+
+	// Dynamic tokens are read from their source once per run
+	if settings.Auth == config.ProviderAuthDynamic {
+		source := settings.Source
+		var token string
+		factory.SetTokenFunc(name, func() (string, error) {
+			if token != "" {
+				return token, nil
+			}
+			var err error
+			if token, err = credentials.ExternalToken(source, name); err != nil {
+				return "", fmt.Errorf("failed to read %s token from %s: %w", key, source, err)
+			}
+			return token, nil
+		})
 	}
-	return b
 }
 
 // providerRemoveCmd represents the provider remove command
 var providerRemoveCmd = &cobra.Command{
 	Use:   "remove [provider]",
 	Short: "Remove a provider connection",
-	Long:  `Remove a provider connection.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Remove a provider connection. Use provider@account to remove a named
+account; routes pointing at it are removed as well.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		providerName := strings.ToLower(args[0])
-		cfg := config.Get()
-
-		if cfg.Providers == nil {
-			return fmt.Errorf("no providers configured")
+		routes, err := config.RemoveProvider(providerName)
+		if err != nil {
+			return err
 		}
-
-		if _, exists := cfg.Providers[providerName]; !exists {
-			return fmt.Errorf("provider '%s' not found", providerName)
+		if routes > 0 {
+			fmt.Printf("✓ Removed %d route(s) for %s\n", routes, providerName)
 		}
 
-		delete(cfg.Providers, providerName)
-		config.UpdateProviders(cfg.Providers)
-
 		fmt.Printf("✓ Removed provider: %s\n", providerName)
 		return nil
 	},
@@ -167,8 +377,14 @@ func init() {
 	providerCmd.AddCommand(providerAddCmd)
 	providerCmd.AddCommand(providerListCmd)
 	providerCmd.AddCommand(providerRemoveCmd)
+	providerCmd.AddCommand(providerStatusCmd)
 
 	providerAddCmd.Flags().StringP("token", "t", "", "Provider token (GitHub/GitLab)")
 	providerAddCmd.Flags().StringP("username", "u", "", "Bitbucket username")
 	providerAddCmd.Flags().StringP("password", "p", "", "Bitbucket app password")
+	providerAddCmd.Flags().Bool("oauth", false, "Authorize GitHub or GitLab with the OAuth device flow instead of a token")
+	providerAddCmd.Flags().Bool("no-validate", false, "Save the credentials without checking them")
+	providerAddCmd.Flags().StringP("account", "a", "", "Name for an additional account of this provider")
+	providerAddCmd.Flags().String("from", "", "Import the token from gh, glab, git-credential or env")
+	providerAddCmd.Flags().Bool("dynamic", false, "Read the token from the --from source each time instead of storing it")
 }
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gitkraken/gk-cli/internal/api"
+	"github.com/gitkraken/gk-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// providerRouteCmd represents the provider route command
+var providerRouteCmd = &cobra.Command{
+	Use:   "route",
+	Short: "Choose which account is used for which repositories",
+	Long: `Manage routes that send repositories to a named provider account.
+
+A route matches host/owner/repo with glob patterns; missing trailing segments
+match anything. The first matching route wins, and repositories without a
+matching route use the provider's default account.
+
+Examples:
+  gk provider route add 'github.com/acme/*' github@work
+  gk provider route add gitlab.example.com gitlab@corp`,
+}
+
+// providerRouteAddCmd represents the provider route add command
+var providerRouteAddCmd = &cobra.Command{
+	Use:   "add <pattern> <provider@account>",
+	Short: "Add a route",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pattern, account := strings.Trim(args[0], "/"), strings.ToLower(args[1])
+		cfg := config.Get()
+
+		if _, ok := cfg.Providers[account]; !ok {
+			return fmt.Errorf("provider account '%s' not found. Add it with: gk provider add %s", account, account)
+		}
+		if _, name := api.SplitAccountKey(account); name == "" {
+			return fmt.Errorf("routes must point at a named account like github@work")
+		}
+
+		routes := cfg.Routes
+		for i, r := range routes {
+			if r.Match == pattern {
+				routes[i].Account = account
+				if err := config.UpdateRoutes(routes); err != nil {
+					return fmt.Errorf("failed to save routes: %w", err)
+				}
+				fmt.Printf("✓ Updated route: %s → %s\n", pattern, account)
+				return nil
+			}
+		}
+
+		routes = append(routes, config.RouteConfig{Match: pattern, Account: account})
+		if err := config.UpdateRoutes(routes); err != nil {
+			return fmt.Errorf("failed to save routes: %w", err)
+		}
+		fmt.Printf("✓ Added route: %s → %s\n", pattern, account)
+		return nil
+	},
+}
+
+// providerRouteListCmd represents the provider route list command
+var providerRouteListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List routes in the order they are matched",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.Get()
+		if len(cfg.Routes) == 0 {
+			fmt.Println("No routes configured.")
+			fmt.Println("Add a route with: gk provider route add <pattern> <provider@account>")
+			return nil
+		}
+		for i, r := range cfg.Routes {
+			fmt.Printf("%d. %s → %s\n", i+1, r.Match, r.Account)
+		}
+		return nil
+	},
+}
+
+// providerRouteRemoveCmd represents the provider route remove command
+var providerRouteRemoveCmd = &cobra.Command{
+	Use:   "remove <pattern>",
+	Short: "Remove a route",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		pattern := strings.Trim(args[0], "/")
+		cfg := config.Get()
+
+		var routes []config.RouteConfig
+		for _, r := range cfg.Routes {
+			if r.Match != pattern {
+				routes = append(routes, r)
+			}
+		}
+		if len(routes) == len(cfg.Routes) {
+			return fmt.Errorf("route '%s' not found", pattern)
+		}
+		if err := config.UpdateRoutes(routes); err != nil {
+			return fmt.Errorf("failed to save routes: %w", err)
+		}
+		fmt.Printf("✓ Removed route: %s\n", pattern)
+		return nil
+	},
+}
+
+func init() {
+	providerCmd.AddCommand(providerRouteCmd)
+	providerRouteCmd.AddCommand(providerRouteAddCmd)
+	providerRouteCmd.AddCommand(providerRouteListCmd)
+	providerRouteCmd.AddCommand(providerRouteRemoveCmd)
+}
@@ -0,0 +1,340 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/gitkraken/gk-cli/internal/api"
+	"github.com/gitkraken/gk-cli/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+// releaseCmd represents the release command
+var releaseCmd = &cobra.Command{
+	Use:   "release",
+	Short: "Manage releases",
+	Long: `Manage releases for the repository in the current directory, or for every
+repository in a workspace with --workspace. Releases map to GitHub releases,
+GitLab releases and Bitbucket tags (with assets stored as downloads).`,
+}
+
+// releaseTarget is a repository a release command operates on
+type releaseTarget struct {
+	name     string
+	path     string
+	remote   string
+	provider string
+	owner    string
+	repo     string
+}
+
+// getReleaseTargets returns the current repository, or every repository in
+// the workspace when --workspace is set
+func getReleaseTargets() ([]releaseTarget, error) {
+	var repos []workspace.Repo
+	if workspaceName != "" {
+		ws, err := workspace.Load(workspaceName)
+		if err != nil {
+			return nil, err
+		}
+		repos = ws.Repos
+	} else {
+		cwd, _ := os.Getwd()
+		repo, err := workspace.DetectRepo(cwd)
+		if err != nil {
+			return nil, fmt.Errorf("not in a git repository. Run from a git repo or use --workspace")
+		}
+		repos = []workspace.Repo{*repo}
+	}
+
+	var targets []releaseTarget
+	for _, repo := range repos {
+		if repo.Remote == "" {
+			if workspaceName == "" {
+				return nil, fmt.Errorf("repository has no remote URL")
+			}
+			continue
+		}
+		providerName, owner, repoName, err := api.ParseRepoURL(repo.Remote)
+		if err != nil {
+			if workspaceName == "" {
+				return nil, fmt.Errorf("failed to parse repository URL: %w", err)
+			}
+			fmt.Printf("⚠ Skipping %s: %v\n", repo.Name, err)
+			continue
+		}
+		targets = append(targets, releaseTarget{
+			name:     repo.Name,
+			path:     repo.Path,
+			remote:   repo.Remote,
+			provider: providerName,
+			owner:    owner,
+			repo:     repoName,
+		})
+	}
+	return targets, nil
+}
+
+// getReleaseManager returns the release client for a target's provider,
+// using the account its remote is routed to
+func getReleaseManager(factory *api.ProviderFactory, target releaseTarget) (api.Provider, api.ReleaseManager, error) {
+	provider, err := factory.ForRemote(target.remote).GetProvider(target.provider)
+	if err != nil {
+		return nil, nil, err
+	}
+	manager, ok := provider.(api.ReleaseManager)
+	if !ok {
+		return nil, nil, fmt.Errorf("releases are not supported for %s", target.provider)
+	}
+	return provider, manager, nil
+}
+
+// releaseListCmd represents the release list command
+var releaseListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List releases",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, _ := cmd.Flags().GetInt("limit")
+		targets, err := getReleaseTargets()
+		if err != nil {
+			return err
+		}
+
+		factory := newProviderFactory()
+		for _, target := range targets {
+			_, manager, err := getReleaseManager(factory, target)
+			if err != nil {
+				fmt.Printf("⚠ Skipping %s: %v\n", target.name, err)
+				continue
+			}
+
+			releases, err := manager.ListReleases(target.owner, target.repo)
+			if err != nil {
+				fmt.Printf("⚠ Error fetching releases for %s: %v\n", target.name, err)
+				continue
+			}
+
+			fmt.Printf("📦 %s/%s (%s):\n", target.owner, target.repo, target.provider)
+			if len(releases) == 0 {
+				fmt.Println("  No releases found.")
+			}
+			for i, r := range releases {
+				if limit > 0 && i >= limit {
+					break
+				}
+				fmt.Printf("  🏷  %s%s  %s\n", r.TagName, releaseFlags(r), r.Name)
+				if date := releaseDate(r); date != "" {
+					fmt.Printf("     %s | %s\n", date, r.URL)
+				}
+			}
+			fmt.Println()
+		}
+		return nil
+	},
+}
+
+// releaseViewCmd represents the release view command
+var releaseViewCmd = &cobra.Command{
+	Use:   "view <tag>",
+	Short: "View a release",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tag := args[0]
+		targets, err := getReleaseTargets()
+		if err != nil {
+			return err
+		}
+
+		factory := newProviderFactory()
+		for _, target := range targets {
+			_, manager, err := getReleaseManager(factory, target)
+			if err != nil {
+				fmt.Printf("⚠ Skipping %s: %v\n", target.name, err)
+				continue
+			}
+
+			r, err := manager.GetRelease(target.owner, target.repo, tag)
+			if err != nil {
+				fmt.Printf("⚠ %s: release %s not found: %v\n", target.name, tag, err)
+				continue
+			}
+
+			fmt.Printf("\n%s Release %s%s: %s\n", strings.ToUpper(target.provider), r.TagName, releaseFlags(*r), r.Name)
+			fmt.Println(strings.Repeat("=", 60))
+			fmt.Printf("Repo:      %s\n", r.Repo)
+			if r.Author != "" {
+				fmt.Printf("Author:    %s\n", r.Author)
+			}
+			if r.Target != "" {
+				fmt.Printf("Target:    %s\n", r.Target)
+			}
+			fmt.Printf("URL:       %s\n", r.URL)
+			fmt.Printf("Published: %s\n", releaseDate(*r))
+			if len(r.Assets) > 0 {
+				fmt.Println("\nAssets:")
+				for _, asset := range r.Assets {
+					fmt.Printf("  • %s  %s\n", asset.Name, asset.URL)
+				}
+			}
+			if r.Body != "" {
+				fmt.Println("\nNotes:")
+				fmt.Println(strings.Repeat("-", 60))
+				fmt.Println(r.Body)
+			}
+		}
+		return nil
+	},
+}
+
+// releaseCreateCmd represents the release create command
+var releaseCreateCmd = &cobra.Command{
+	Use:   "create <tag>",
+	Short: "Create a release",
+	Long: `Create a release for a tag. The tag is created from --target (default: the
+local HEAD when available, otherwise the default branch). Use --generate-notes
+to build notes from the pull requests merged since the previous release, and
+--asset to attach local files.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		opts := api.ReleaseOptions{TagName: args[0]}
+		opts.Name, _ = cmd.Flags().GetString("title")
+		opts.Body, _ = cmd.Flags().GetString("notes")
+		opts.Target, _ = cmd.Flags().GetString("target")
+		opts.Draft, _ = cmd.Flags().GetBool("draft")
+		opts.Prerelease, _ = cmd.Flags().GetBool("prerelease")
+		opts.Assets, _ = cmd.Flags().GetStringSlice("asset")
+		generateNotes, _ := cmd.Flags().GetBool("generate-notes")
+
+		if notesFile, _ := cmd.Flags().GetString("notes-file"); notesFile != "" {
+			data, err := os.ReadFile(notesFile)
+			if err != nil {
+				return fmt.Errorf("failed to read notes file: %w", err)
+			}
+			opts.Body = string(data)
+		}
+		if opts.Name == "" {
+			opts.Name = opts.TagName
+		}
+		for _, asset := range opts.Assets {
+			if _, err := os.Stat(asset); err != nil {
+				return fmt.Errorf("asset not found: %s", asset)
+			}
+		}
+
+		targets, err := getReleaseTargets()
+		if err != nil {
+			return err
+		}
+
+		factory := newProviderFactory()
+		failed := 0
+		for _, target := range targets {
+			provider, manager, err := getReleaseManager(factory, target)
+			if err != nil {
+				fmt.Printf("⚠ Skipping %s: %v\n", target.name, err)
+				failed++
+				continue
+			}
+
+			repoOpts := opts
+			if repoOpts.Target == "" && target.path != "" {
+				repoOpts.Target = localHead(target.path)
+			}
+
+			if generateNotes {
+				notes, err := releaseNotes(provider, manager, target)
+				if err != nil {
+					fmt.Printf("⚠ %s: %v\n", target.name, err)
+				} else {
+					repoOpts.Body = strings.TrimSpace(repoOpts.Body + "\n\n" + notes)
+				}
+			}
+
+			r, err := manager.CreateRelease(target.owner, target.repo, repoOpts)
+			if err != nil {
+				fmt.Printf("✗ %s: failed to create release: %v\n", target.name, err)
+				failed++
+				continue
+			}
+
+			fmt.Printf("✓ Created release %s%s for %s\n", r.TagName, releaseFlags(*r), r.Repo)
+			if r.URL != "" {
+				fmt.Printf("  %s\n", r.URL)
+			}
+			for _, asset := range r.Assets {
+				fmt.Printf("  • %s\n", asset.Name)
+			}
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("failed to create %d release(s)", failed)
+		}
+		return nil
+	},
+}
+
+// releaseNotes generates notes from the PRs merged since the latest release
+func releaseNotes(provider api.Provider, manager api.ReleaseManager, target releaseTarget) (string, error) {
+	var since time.Time
+	releases, err := manager.ListReleases(target.owner, target.repo)
+	if err != nil {
+		return "", fmt.Errorf("failed to find previous release: %w", err)
+	}
+	if latest := api.LatestRelease(releases); latest != nil {
+		since, _ = time.Parse(time.RFC3339, releaseDate(*latest))
+	}
+	return api.GenerateReleaseNotes(provider, target.owner, target.repo, since)
+}
+
+// localHead returns the commit checked out in a local repository, or ""
+func localHead(path string) string {
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = path
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func releaseFlags(r api.Release) string {
+	var flags []string
+	if r.Draft {
+		flags = append(flags, "draft")
+	}
+	if r.Prerelease {
+		flags = append(flags, "prerelease")
+	}
+	if len(flags) == 0 {
+		return ""
+	}
+	return " (" + strings.Join(flags, ", ") + ")"
+}
+
+func releaseDate(r api.Release) string {
+	if r.PublishedAt != "" {
+		return r.PublishedAt
+	}
+	return r.CreatedAt
+}
+
+func init() {
+	rootCmd.AddCommand(releaseCmd)
+	releaseCmd.AddCommand(releaseListCmd)
+	releaseCmd.AddCommand(releaseViewCmd)
+	releaseCmd.AddCommand(releaseCreateCmd)
+
+	releaseCmd.PersistentFlags().StringVarP(&workspaceName, "workspace", "w", "", "operate on every repository in a workspace")
+	releaseListCmd.Flags().IntP("limit", "L", 10, "Maximum releases to show per repository")
+	releaseCreateCmd.Flags().StringP("title", "t", "", "Release title (default: tag name)")
+	releaseCreateCmd.Flags().StringP("notes", "n", "", "Release notes")
+	releaseCreateCmd.Flags().StringP("notes-file", "F", "", "Read release notes from file")
+	releaseCreateCmd.Flags().Bool("generate-notes", false, "Generate notes from PRs merged since the previous release")
+	releaseCreateCmd.Flags().String("target", "", "Branch or commit to tag (default: local HEAD)")
+	releaseCreateCmd.Flags().Bool("draft", false, "Create a draft release (GitHub only)")
+	releaseCreateCmd.Flags().Bool("prerelease", false, "Mark as prerelease (GitHub only)")
+	releaseCreateCmd.Flags().StringSliceP("asset", "a", nil, "Local file to attach (repeatable)")
+}
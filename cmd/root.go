@@ -3,15 +3,18 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/gitkraken/gk-cli/internal/config"
+	"github.com/gitkraken/gk-cli/internal/workspace"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var (
-	cfgFile string
-	version = "dev"
+	cfgFile     string
+	profileName string
+	version     = "dev"
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -34,36 +37,40 @@ func init() {
 	cobra.OnInitialize(initConfig)
 
 	// Global flags
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.config/gk/config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $GK_CONFIG or $HOME/.config/gk/config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "configuration profile to use (default is $GK_PROFILE or 'gk profile use')")
 	rootCmd.PersistentFlags().Bool("verbose", false, "verbose output")
 }
 
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
-	if cfgFile != "" {
-		// Use config file from the flag.
-		viper.SetConfigFile(cfgFile)
-	} else {
-		// Find home directory.
-		home, err := os.UserHomeDir()
-		cobra.CheckErr(err)
+	// Use config file from the flag, or GK_CONFIG
+	if cfgFile == "" {
+		cfgFile = os.Getenv("GK_CONFIG")
+	}
+	config.SetFile(cfgFile)
 
-		// Search config in home directory with name ".gk" (without extension).
-		viper.AddConfigPath(home + "/.config/gk")
-		viper.AddConfigPath(home + "/.gk")
-		viper.SetConfigType("yaml")
-		viper.SetConfigName("config")
+	// Move files from where older versions kept them
+	if err := config.MigrateLegacyPaths(); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠ %v\n", err)
 	}
 
-	viper.AutomaticEnv() // read in environment variables that match
+	// Select the profile before anything reads its files; config.Init
+	// searches the profile's directory for the config file
+	cobra.CheckErr(config.SetProfile(profileName))
+	if dir, err := config.Dir(); err == nil {
+		workspace.SetDir(filepath.Join(dir, "workspaces"))
+	}
 
-	// Initialize config system
+	// Initialize config system; GK_ environment variables are applied on
+	// top of the file by config.Get
 	if err := config.Init(); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: Failed to initialize config: %v\n", err)
 	}
 
-	// If a config file is found, read it in.
-	if err := viper.ReadInConfig(); err == nil {
-		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
+	if used := viper.ConfigFileUsed(); used != "" {
+		if _, err := os.Stat(used); err == nil {
+			fmt.Fprintln(os.Stderr, "Using config file:", used)
+		}
 	}
 }
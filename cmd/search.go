@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gitkraken/gk-cli/internal/api"
+	"github.com/spf13/cobra"
+)
+
+// searchCmd represents the search command
+var searchCmd = &cobra.Command{
+	Use:   "search",
+	Short: "Search pull requests and issues across providers",
+	Long: `Search pull requests and issues across all connected providers. Qualifiers
+are translated to each provider's search syntax and the results are merged.
+
+Examples:
+  gk search prs auth --involves alice --org acme
+  gk search issues "login fails" --label bug --repo acme/api`,
+}
+
+// searchPRsCmd represents the search prs command
+var searchPRsCmd = &cobra.Command{
+	Use:   "prs [query]",
+	Short: "Search pull requests",
+	Long:  `Search pull requests on GitHub, GitLab and Bitbucket.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		q, providers, err := searchQueryFromFlags(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		factory := newProviderFactory()
+		var results []api.PullRequest
+		for _, name := range providers {
+			searcher, err := getSearcher(factory, name)
+			if err != nil {
+				fmt.Printf("⚠ Skipping %s: %v\n", name, err)
+				continue
+			}
+			prs, err := searcher.SearchPullRequests(q)
+			if err != nil {
+				fmt.Printf("⚠ Error searching %s: %v\n", name, err)
+				continue
+			}
+			results = append(results, prs...)
+		}
+
+		if len(results) == 0 {
+			fmt.Println("No pull requests found.")
+			return nil
+		}
+
+		api.SortPullRequestsByUpdated(results)
+		for _, pr := range results {
+			status := "🟢"
+			if pr.State != "open" && pr.State != "opened" {
+				status = "🔴"
+			}
+			fmt.Printf("%s %s %s#%d: %s\n", status, pr.Provider, pr.Repo, pr.Number, pr.Title)
+			fmt.Printf("     Author: %s | %s\n", pr.Author, pr.URL)
+		}
+		fmt.Printf("\nTotal: %d pull request(s)\n", len(results))
+		return nil
+	},
+}
+
+// searchIssuesCmd represents the search issues command
+var searchIssuesCmd = &cobra.Command{
+	Use:   "issues [query]",
+	Short: "Search issues",
+	Long:  `Search issues on GitHub, GitLab and Bitbucket.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		q, providers, err := searchQueryFromFlags(cmd, args)
+		if err != nil {
+			return err
+		}
+
+		factory := newProviderFactory()
+		var results []api.Issue
+		for _, name := range providers {
+			searcher, err := getSearcher(factory, name)
+			if err != nil {
+				fmt.Printf("⚠ Skipping %s: %v\n", name, err)
+				continue
+			}
+			issues, err := searcher.SearchIssues(q)
+			if err != nil {
+				fmt.Printf("⚠ Error searching %s: %v\n", name, err)
+				continue
+			}
+			results = append(results, issues...)
+		}
+
+		if len(results) == 0 {
+			fmt.Println("No issues found.")
+			return nil
+		}
+
+		api.SortIssuesByUpdated(results)
+		for _, issue := range results {
+			fmt.Printf("⚪ %s %s#%d: %s\n", issue.Provider, issue.Repo, issue.Number, issue.Title)
+			fmt.Printf("     Author: %s | %s\n", issue.Author, issue.URL)
+			if len(issue.Labels) > 0 {
+				fmt.Printf("     Labels: %s\n", strings.Join(issue.Labels, ", "))
+			}
+		}
+		fmt.Printf("\nTotal: %d issue(s)\n", len(results))
+		return nil
+	},
+}
+
+// searchQueryFromFlags builds the search query and the list of providers to
+// search from the command line
+func searchQueryFromFlags(cmd *cobra.Command, args []string) (api.SearchQuery, []string, error) {
+	q := api.SearchQuery{Text: strings.Join(args, " ")}
+	q.Author, _ = cmd.Flags().GetString("author")
+	q.Labels, _ = cmd.Flags().GetStringSlice("label")
+	q.Involves, _ = cmd.Flags().GetString("involves")
+	q.Repo, _ = cmd.Flags().GetString("repo")
+	q.Org, _ = cmd.Flags().GetString("org")
+	q.State, _ = cmd.Flags().GetString("state")
+
+	if q.Text == "" && q.Author == "" && len(q.Labels) == 0 && q.Involves == "" && q.Repo == "" && q.Org == "" {
+		return q, nil, fmt.Errorf("a query or at least one qualifier is required")
+	}
+
+	providers, _ := cmd.Flags().GetStringSlice("provider")
+	skippedBitbucket := false
+	if len(providers) == 0 {
+		for _, name := range newProviderFactory().ConfiguredProviders() {
+			// Bitbucket can only search a repository or workspace, so it is
+			// left out unless one is given or it is asked for by name
+			if name == "bitbucket" && q.Repo == "" && q.Org == "" {
+				skippedBitbucket = true
+				continue
+			}
+			providers = append(providers, name)
+		}
+	}
+	if len(providers) == 0 && skippedBitbucket {
+		return q, nil, fmt.Errorf("Bitbucket can only be searched within a repository or workspace: use --repo or --org")
+	}
+	if len(providers) == 0 {
+		return q, nil, fmt.Errorf("no providers configured. Add one with: gk provider add <github|gitlab|bitbucket>")
+	}
+
+	return q, providers, nil
+}
+
+// getSearcher returns the named provider if it supports searching
+func getSearcher(factory *api.ProviderFactory, name string) (api.Searcher, error) {
+	provider, err := factory.GetProvider(name)
+	if err != nil {
+		return nil, err
+	}
+	searcher, ok := provider.(api.Searcher)
+	if !ok {
+		return nil, fmt.Errorf("search is not supported for %s", name)
+	}
+	return searcher, nil
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+	searchCmd.AddCommand(searchPRsCmd)
+	searchCmd.AddCommand(searchIssuesCmd)
+
+	for _, c := range []*cobra.Command{searchPRsCmd, searchIssuesCmd} {
+		c.Flags().String("author", "", "Filter by author username")
+		c.Flags().StringSlice("label", nil, "Filter by label (repeatable)")
+		c.Flags().String("involves", "", "Filter by a user who authored, is assigned or was asked to review")
+		c.Flags().String("repo", "", "Limit to a repository (owner/name)")
+		c.Flags().String("org", "", "Limit to an organization, group or workspace")
+		c.Flags().StringP("state", "s", "open", "Filter by state (open, closed, all)")
+		c.Flags().StringSlice("provider", nil, "Providers to search (default: all configured; Bitbucket only with --repo or --org)")
+	}
+}
@@ -6,9 +6,15 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
+	"text/tabwriter"
+	"time"
 
+	"github.com/gitkraken/gk-cli/internal/config"
 	"github.com/gitkraken/gk-cli/internal/workspace"
+	"github.com/gitkraken/gk-cli/pkg/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -24,13 +30,30 @@ var workspaceCmd = &cobra.Command{
 the context for helpful commands that can operate on multiple repos at once.`,
 }
 
-// getWorkspace gets the workspace to use (from flag or prompts)
+// getWorkspace gets the workspace to use: the -w flag, a workspace set by
+// GK_WORKSPACE or the repository's .gk.yaml, the workspace containing the
+// current repository, the default set with 'gk ws use', the only workspace,
+// or a prompt
 func getWorkspace() (*workspace.Workspace, error) {
 	if workspaceName != "" {
 		return workspace.Load(workspaceName)
 	}
 
-	// Try to get default workspace
+	name := config.Get().Workspace
+	if name != "" && config.Origin("workspace") != "file:"+config.Path() {
+		return workspace.Load(name)
+	}
+
+	if cwd, err := os.Getwd(); err == nil {
+		if ws, err := workspace.FindByPath(cwd); err == nil && ws != nil {
+			return ws, nil
+		}
+	}
+
+	if name != "" {
+		return workspace.Load(name)
+	}
+
 	workspaces, err := workspace.List()
 	if err != nil {
 		return nil, err
@@ -44,7 +67,10 @@ func getWorkspace() (*workspace.Workspace, error) {
 		return workspace.Load(workspaces[0])
 	}
 
-	// Multiple workspaces - prompt user
+	// Multiple workspaces - prompt user, unless nobody is there to answer
+	if !utils.IsTerminal() {
+		return nil, fmt.Errorf("several workspaces found (%s). Choose one with -w or set a default with 'gk ws use <name>'", strings.Join(workspaces, ", "))
+	}
 	fmt.Println("Available workspaces:")
 	for i, ws := range workspaces {
 		fmt.Printf("  %d. %s\n", i+1, ws)
@@ -55,13 +81,12 @@ func getWorkspace() (*workspace.Workspace, error) {
 	input = strings.TrimSpace(input)
 
 	// Try as number first
-	var selected string
-	if idx := 0; len(input) == 1 && input[0] >= '1' && input[0] <= '9' {
-		if int(input[0]-'0') <= len(workspaces) {
-			selected = workspaces[int(input[0]-'0')-1]
+	selected := input
+	if n, err := strconv.Atoi(input); err == nil {
+		if n < 1 || n > len(workspaces) {
+			return nil, fmt.Errorf("invalid workspace selection")
 		}
-	} else {
-		selected = input
+		selected = workspaces[n-1]
 	}
 
 	if selected == "" {
@@ -71,6 +96,114 @@ func getWorkspace() (*workspace.Workspace, error) {
 	return workspace.Load(selected)
 }
 
+// printRepoResult prints the captured output of a repository as one block
+func printRepoResult(r workspace.Result) {
+	mark := "✓"
+	if r.Err != nil {
+		mark = "✗"
+	}
+	fmt.Printf("%s %s (%s)\n", mark, r.Repo.Name, r.Duration.Round(10*time.Millisecond))
+	for _, line := range strings.Split(strings.TrimRight(string(r.Output), "\n"), "\n") {
+		if line != "" {
+			fmt.Printf("    %s\n", line)
+		}
+	}
+	if r.Err != nil {
+		fmt.Printf("    %v\n", r.Err)
+	}
+}
+
+// summarizeResults prints a table of how each repository did and returns
+// an error if any of them failed
+func summarizeResults(results []workspace.Result) error {
+	fmt.Println("\nSummary:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	skipped := 0
+	for _, r := range results {
+		switch {
+		case r.Skipped:
+			skipped++
+			fmt.Fprintf(w, "  - %s\t%s\t\t\n", r.Repo.Name, "skipped")
+		case r.Err != nil:
+			fmt.Fprintf(w, "  ✗ %s\t%s\t%s\t%v\n", r.Repo.Name, "failed", r.Duration.Round(10*time.Millisecond), r.Err)
+		default:
+			fmt.Fprintf(w, "  ✓ %s\t%s\t%s\t\n", r.Repo.Name, "ok", r.Duration.Round(10*time.Millisecond))
+		}
+	}
+	w.Flush()
+
+	failed := len(workspace.Failed(results))
+	fmt.Printf("\n%d succeeded, %d failed", len(results)-failed-skipped, failed)
+	if skipped > 0 {
+		fmt.Printf(", %d skipped", skipped)
+	}
+	fmt.Println()
+	if failed > 0 {
+		return fmt.Errorf("%d of %d repositories failed", failed, len(results))
+	}
+	return nil
+}
+
+// wsExecCmd represents the ws exec command
+var wsExecCmd = &cobra.Command{
+	Use:   "exec [flags] -- <command> [args...]",
+	Short: "Run a command in every repository of a workspace",
+	Long: `Run a command in every repository of a workspace that has a local path,
+e.g. 'make test' or 'go mod tidy'. Repositories are processed in parallel and
+each one's output is printed as a block when it finishes, followed by a
+summary. The command gets GK_REPO_NAME and GK_REPO_REMOTE in its environment;
+use 'sh -c' for shell syntax. Exits with an error if the command failed in
+any repository.
+
+Examples:
+  gk ws exec -- make test
+  gk ws exec --fail-fast -j 1 -- go mod tidy
+  gk ws exec -- sh -c 'echo "$GK_REPO_NAME: $(git rev-parse --abbrev-ref HEAD)"'`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ws, err := getWorkspace()
+		if err != nil {
+			return err
+		}
+
+		jobs, _ := cmd.Flags().GetInt("jobs")
+		failFast, _ := cmd.Flags().GetBool("fail-fast")
+		fmt.Printf("Running '%s' in all repositories in workspace '%s'...\n\n", strings.Join(args, " "), ws.Name)
+		results, err := workspace.Run(ws, args[0], args[1:], workspace.RunOptions{
+			Jobs:     jobs,
+			FailFast: failFast,
+			OnDone:   printRepoResult,
+		})
+		if err != nil {
+			return err
+		}
+
+		cmd.SilenceUsage = true
+		return summarizeResults(results)
+	},
+}
+
+// wsUseCmd represents the ws use command
+var wsUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Set the default workspace",
+	Long: `Set the workspace commands use when -w isn't given. A workspace containing
+the current repository, or one set in the repository's .gk.yaml, still takes
+precedence. Remove the default with 'gk config unset workspace'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ws, err := workspace.Load(args[0])
+		if err != nil {
+			return err
+		}
+		if err := config.SetValue("workspace", ws.Name); err != nil {
+			return fmt.Errorf("failed to set default workspace: %w", err)
+		}
+		fmt.Printf("✓ Default workspace set to '%s'\n", ws.Name)
+		return nil
+	},
+}
+
 // wsCreateCmd represents the ws create command
 var wsCreateCmd = &cobra.Command{
 	Use:   "create [name]",
@@ -104,7 +237,7 @@ or cloud (accessible on any machine and can be connected to hosting providers).`
 			return fmt.Errorf("failed to create workspace: %w", err)
 		}
 
-		fmt.Printf("✓ Created %s workspace '%s'\n", wsType, name)
+		fmt.Printf("✓ Created %s workspace '%s'\n", ws.Type, ws.Name)
 		return nil
 	},
 }
@@ -296,6 +429,8 @@ func init() {
 	workspaceCmd.AddCommand(wsLocateCmd)
 	workspaceCmd.AddCommand(wsCloneCmd)
 	workspaceCmd.AddCommand(wsInsightsCmd)
+	workspaceCmd.AddCommand(wsUseCmd)
+	workspaceCmd.AddCommand(wsExecCmd)
 
 	// Git operations on workspaces
 	gitOps := []string{"fetch", "pull", "push", "checkout"}
@@ -304,21 +439,32 @@ func init() {
 		opCmd := &cobra.Command{
 			Use:   op + " [git-args...]",
 			Short: fmt.Sprintf("Perform '%s' operation on all repos in workspace", op),
+			Long: fmt.Sprintf(`Perform 'git %s' on all repos in the workspace, several at once.
+
+Git can't prompt for passwords or passphrases while repos run in parallel, so
+credentials must come from a credential helper or an ssh agent. Use -j 1 to
+enter them interactively.`, op),
 			RunE: func(cmd *cobra.Command, args []string) error {
 				ws, err := getWorkspace()
 				if err != nil {
 					return err
 				}
 
-				fmt.Printf("Running 'git %s' on all repositories in workspace '%s'...\n", op, ws.Name)
-				if err := workspace.GitOperation(ws, op, args); err != nil {
+				jobs, _ := cmd.Flags().GetInt("jobs")
+				fmt.Printf("Running 'git %s' on all repositories in workspace '%s'...\n\n", op, ws.Name)
+				results, err := workspace.GitOperation(ws, op, args, workspace.RunOptions{
+					Jobs:   jobs,
+					OnDone: printRepoResult,
+				})
+				if err != nil {
 					return fmt.Errorf("git operation failed: %w", err)
 				}
 
-				fmt.Println("✓ Completed")
-				return nil
+				cmd.SilenceUsage = true
+				return summarizeResults(results)
 			},
 		}
+		opCmd.Flags().IntP("jobs", "j", runtime.NumCPU(), "number of repositories to run at once")
 		workspaceCmd.AddCommand(opCmd)
 	}
 
@@ -326,4 +472,8 @@ func init() {
 	workspaceCmd.PersistentFlags().StringVarP(&workspaceName, "workspace", "w", "", "workspace name")
 	wsCreateCmd.Flags().StringP("type", "t", "local", "workspace type (local or cloud)")
 	wsCreateCmd.Flags().StringP("description", "d", "", "workspace description")
+	wsExecCmd.Flags().IntP("jobs", "j", runtime.NumCPU(), "number of repositories to run at once")
+	wsExecCmd.Flags().Bool("fail-fast", false, "don't start further repositories once one fails")
+	// Flags after the command belong to it, so '--' is optional
+	wsExecCmd.Flags().SetInterspersed(false)
 }
@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"fmt"
-	"os"
 
 	"github.com/gitkraken/gk-cli/internal/workspace"
 	"github.com/spf13/cobra"
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Route sends repositories matching a host/owner/repo glob, such as
+// github.com/acme/*, to a named account like github@work. Missing trailing
+// segments match anything, so github.com/acme covers every acme repository.
+type Route struct {
+	Match   string
+	Account string
+}
+
+// AccountKey returns the config key for a provider account; the default
+// account is keyed by the provider name alone
+func AccountKey(provider, account string) string {
+	if account == "" {
+		return provider
+	}
+	return provider + "@" + account
+}
+
+// SplitAccountKey splits an account key like github@work into its provider
+// and account name
+func SplitAccountKey(key string) (provider, account string) {
+	provider, account, _ = strings.Cut(key, "@")
+	return provider, account
+}
+
+// Account returns the factory holding the credentials of a named account,
+// creating it on first use
+func (f *ProviderFactory) Account(key string) *ProviderFactory {
+	if f.accounts == nil {
+		f.accounts = make(map[string]*ProviderFactory)
+	}
+	if _, ok := f.accounts[key]; !ok {
+		f.accounts[key] = NewProviderFactory()
+	}
+	return f.accounts[key]
+}
+
+// SetRoutes sets the rules choosing an account for each repository. The
+// first matching route wins; unmatched repositories use the default account.
+func (f *ProviderFactory) SetRoutes(routes []Route) {
+	f.routes = routes
+}
+
+// RouteAccount returns the account key the routes select for a repository
+func (f *ProviderFactory) RouteAccount(provider, host, owner, repo string) string {
+	for _, route := range f.routes {
+		routeProvider, _ := SplitAccountKey(route.Account)
+		if routeProvider == provider && RouteMatches(route.Match, host, owner, repo) {
+			return route.Account
+		}
+	}
+	return provider
+}
+
+// ForRepo returns the factory whose credentials apply to a repository
+func (f *ProviderFactory) ForRepo(provider, host, owner, repo string) *ProviderFactory {
+	key := f.RouteAccount(provider, host, owner, repo)
+	if key == provider {
+		return f
+	}
+	return f.Account(key)
+}
+
+// ForRemote returns the factory whose credentials apply to a remote URL
+func (f *ProviderFactory) ForRemote(remote string) *ProviderFactory {
+	provider, owner, repo, err := ParseRepoURL(remote)
+	if err != nil {
+		return f
+	}
+	return f.ForRepo(provider, RemoteHost(remote), owner, repo)
+}
+
+// RouteMatches reports whether a host/owner/repo glob matches a repository.
+// The path part also matches when it covers a leading part of owner/repo,
+// so github.com/acme and gitlab.com/group/* match everything beneath them.
+func RouteMatches(pattern, host, owner, repo string) bool {
+	hostPattern, pathPattern, hasPath := strings.Cut(strings.Trim(pattern, "/"), "/")
+	if ok, _ := path.Match(strings.ToLower(hostPattern), strings.ToLower(host)); !ok {
+		return false
+	}
+	if !hasPath {
+		return true
+	}
+
+	segments := strings.Split(owner+"/"+repo, "/")
+	for i := len(segments); i > 0; i-- {
+		if ok, _ := path.Match(pathPattern, strings.Join(segments[:i], "/")); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoteHost returns the host of an HTTPS or SSH remote URL
+func RemoteHost(remote string) string {
+	if u, err := url.Parse(remote); err == nil && u.Host != "" {
+		return u.Hostname()
+	}
+	// scp-like syntax: git@github.com:owner/repo.git
+	if at := strings.Index(remote, "@"); at >= 0 {
+		remote = remote[at+1:]
+	}
+	if colon := strings.Index(remote, ":"); colon >= 0 {
+		return remote[:colon]
+	}
+	return ""
+}
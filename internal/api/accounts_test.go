@@ -0,0 +1,64 @@
+package api
+
+import "testing"
+
+func TestRouteMatches(t *testing.T) {
+	tests := []struct {
+		pattern string
+		host    string
+		owner   string
+		repo    string
+		want    bool
+	}{
+		{"github.com/acme/*", "github.com", "acme", "api", true},
+		{"github.com/acme/*", "github.com", "other", "api", false},
+		{"github.com/acme", "github.com", "acme", "web", true},
+		{"github.com/*/infra-*", "github.com", "acme", "infra-prod", true},
+		{"github.com/*/infra-*", "github.com", "acme", "web", false},
+		{"*.example.com", "git.example.com", "team", "repo", true},
+		{"gitlab.com/group/*", "gitlab.com", "group/sub", "project", true},
+		{"gitlab.com/acme/*", "github.com", "acme", "api", false},
+	}
+
+	for _, tt := range tests {
+		if got := RouteMatches(tt.pattern, tt.host, tt.owner, tt.repo); got != tt.want {
+			t.Errorf("RouteMatches(%q, %s/%s/%s) = %v, want %v", tt.pattern, tt.host, tt.owner, tt.repo, got, tt.want)
+		}
+	}
+}
+
+func TestForRemoteUsesRoutedAccount(t *testing.T) {
+	factory := NewProviderFactory()
+	factory.SetGitHubToken("personal")
+	factory.Account("github@work").SetGitHubToken("work")
+	factory.SetRoutes([]Route{
+		{Match: "github.com/acme/*", Account: "github@work"},
+		{Match: "gitlab.com/*", Account: "gitlab@other"},
+	})
+
+	tests := map[string]string{
+		"git@github.com:acme/api.git":       "work",
+		"https://github.com/alice/dotfiles": "personal",
+	}
+	for remote, want := range tests {
+		provider, err := factory.ForRemote(remote).GetProvider("github")
+		if err != nil {
+			t.Fatalf("%s: %v", remote, err)
+		}
+		if got := provider.(*GitHubProviderAdapter).client.token; got != want {
+			t.Errorf("%s: expected %s token, got %s", remote, want, got)
+		}
+	}
+}
+
+func TestRemoteHost(t *testing.T) {
+	for remote, want := range map[string]string{
+		"https://github.com/acme/api.git":      "github.com",
+		"git@gitlab.com:group/sub/project.git": "gitlab.com",
+		"ssh://git@bitbucket.org/team/repo":    "bitbucket.org",
+	} {
+		if got := RemoteHost(remote); got != want {
+			t.Errorf("RemoteHost(%s) = %s, want %s", remote, got, want)
+		}
+	}
+}
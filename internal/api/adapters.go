@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -27,18 +28,19 @@ func (a *GitHubProviderAdapter) ListPullRequests(owner, repo, state string) ([]P
 	result := make([]PullRequest, len(prs))
 	for i, pr := range prs {
 		result[i] = PullRequest{
-			Provider:      "github",
-			ID:            strconv.Itoa(pr.ID),
-			Number:        pr.Number,
-			Title:         pr.Title,
-			Body:          pr.Body,
-			State:         pr.State,
-			URL:           pr.URL,
-			Author:        pr.User.Login,
-			SourceBranch:  pr.Head.Ref,
-			TargetBranch:  pr.Base.Ref,
-			CreatedAt:     pr.CreatedAt.Format(time.RFC3339),
-			UpdatedAt:     pr.UpdatedAt.Format(time.RFC3339),
+			Provider:     "github",
+			ID:           strconv.Itoa(pr.ID),
+			Number:       pr.Number,
+			Title:        pr.Title,
+			Body:         pr.Body,
+			State:        pr.State,
+			URL:          pr.URL,
+			Author:       pr.User.Login,
+			SourceBranch: pr.Head.Ref,
+			TargetBranch: pr.Base.Ref,
+			CreatedAt:    pr.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:    pr.UpdatedAt.Format(time.RFC3339),
+			MergedAt:     formatOptionalTime(pr.MergedAt),
 		}
 	}
 	return result, nil
@@ -52,18 +54,19 @@ func (a *GitHubProviderAdapter) GetPullRequest(owner, repo string, number int) (
 	}
 
 	return &PullRequest{
-		Provider:      "github",
-		ID:            strconv.Itoa(pr.ID),
-		Number:        pr.Number,
-		Title:         pr.Title,
-		Body:          pr.Body,
-		State:         pr.State,
-		URL:           pr.URL,
-		Author:        pr.User.Login,
-		SourceBranch:  pr.Head.Ref,
-		TargetBranch:  pr.Base.Ref,
-		CreatedAt:     pr.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:     pr.UpdatedAt.Format(time.RFC3339),
+		Provider:     "github",
+		ID:           strconv.Itoa(pr.ID),
+		Number:       pr.Number,
+		Title:        pr.Title,
+		Body:         pr.Body,
+		State:        pr.State,
+		URL:          pr.URL,
+		Author:       pr.User.Login,
+		SourceBranch: pr.Head.Ref,
+		TargetBranch: pr.Base.Ref,
+		CreatedAt:    pr.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:    pr.UpdatedAt.Format(time.RFC3339),
+		MergedAt:     formatOptionalTime(pr.MergedAt),
 	}, nil
 }
 
@@ -87,17 +90,17 @@ func (a *GitHubProviderAdapter) ListIssues(owner, repo, state string) ([]Issue,
 		}
 
 		result = append(result, Issue{
-			Provider:   "github",
-			ID:         strconv.Itoa(issue.ID),
-			Number:     issue.Number,
-			Title:      issue.Title,
-			Body:       issue.Body,
-			State:      issue.State,
-			URL:        issue.URL,
-			Author:     issue.User.Login,
-			Labels:     labels,
-			CreatedAt:  issue.CreatedAt.Format(time.RFC3339),
-			UpdatedAt:  issue.UpdatedAt.Format(time.RFC3339),
+			Provider:  "github",
+			ID:        strconv.Itoa(issue.ID),
+			Number:    issue.Number,
+			Title:     issue.Title,
+			Body:      issue.Body,
+			State:     issue.State,
+			URL:       issue.URL,
+			Author:    issue.User.Login,
+			Labels:    labels,
+			CreatedAt: issue.CreatedAt.Format(time.RFC3339),
+			UpdatedAt: issue.UpdatedAt.Format(time.RFC3339),
 		})
 	}
 	return result, nil
@@ -123,18 +126,19 @@ func (a *GitLabProviderAdapter) ListPullRequests(owner, repo, state string) ([]P
 	result := make([]PullRequest, len(mrs))
 	for i, mr := range mrs {
 		result[i] = PullRequest{
-			Provider:      "gitlab",
-			ID:            strconv.Itoa(mr.ID),
-			Number:        mr.IID,
-			Title:         mr.Title,
-			Body:          mr.Description,
-			State:         mr.State,
-			URL:           mr.URL,
-			Author:        mr.Author.Username,
-			SourceBranch:  mr.SourceBranch,
-			TargetBranch:  mr.TargetBranch,
-			CreatedAt:     mr.CreatedAt.Format(time.RFC3339),
-			UpdatedAt:     mr.UpdatedAt.Format(time.RFC3339),
+			Provider:     "gitlab",
+			ID:           strconv.Itoa(mr.ID),
+			Number:       mr.IID,
+			Title:        mr.Title,
+			Body:         mr.Description,
+			State:        mr.State,
+			URL:          mr.URL,
+			Author:       mr.Author.Username,
+			SourceBranch: mr.SourceBranch,
+			TargetBranch: mr.TargetBranch,
+			CreatedAt:    mr.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:    mr.UpdatedAt.Format(time.RFC3339),
+			MergedAt:     formatOptionalTime(mr.MergedAt),
 		}
 	}
 	return result, nil
@@ -149,18 +153,19 @@ func (a *GitLabProviderAdapter) GetPullRequest(owner, repo string, number int) (
 	}
 
 	return &PullRequest{
-		Provider:      "gitlab",
-		ID:            strconv.Itoa(mr.ID),
-		Number:        mr.IID,
-		Title:         mr.Title,
-		Body:          mr.Description,
-		State:         mr.State,
-		URL:           mr.URL,
-		Author:        mr.Author.Username,
-		SourceBranch:  mr.SourceBranch,
-		TargetBranch:  mr.TargetBranch,
-		CreatedAt:     mr.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:     mr.UpdatedAt.Format(time.RFC3339),
+		Provider:     "gitlab",
+		ID:           strconv.Itoa(mr.ID),
+		Number:       mr.IID,
+		Title:        mr.Title,
+		Body:         mr.Description,
+		State:        mr.State,
+		URL:          mr.URL,
+		Author:       mr.Author.Username,
+		SourceBranch: mr.SourceBranch,
+		TargetBranch: mr.TargetBranch,
+		CreatedAt:    mr.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:    mr.UpdatedAt.Format(time.RFC3339),
+		MergedAt:     formatOptionalTime(mr.MergedAt),
 	}, nil
 }
 
@@ -175,17 +180,17 @@ func (a *GitLabProviderAdapter) ListIssues(owner, repo, state string) ([]Issue,
 	result := make([]Issue, len(issues))
 	for i, issue := range issues {
 		result[i] = Issue{
-			Provider:   "gitlab",
-			ID:         strconv.Itoa(issue.ID),
-			Number:     issue.IID,
-			Title:      issue.Title,
-			Body:       issue.Description,
-			State:      issue.State,
-			URL:        issue.URL,
-			Author:     issue.Author.Username,
-			Labels:     issue.Labels,
-			CreatedAt:  issue.CreatedAt.Format(time.RFC3339),
-			UpdatedAt:  issue.UpdatedAt.Format(time.RFC3339),
+			Provider:  "gitlab",
+			ID:        strconv.Itoa(issue.ID),
+			Number:    issue.IID,
+			Title:     issue.Title,
+			Body:      issue.Description,
+			State:     issue.State,
+			URL:       issue.URL,
+			Author:    issue.Author.Username,
+			Labels:    issue.Labels,
+			CreatedAt: issue.CreatedAt.Format(time.RFC3339),
+			UpdatedAt: issue.UpdatedAt.Format(time.RFC3339),
 		}
 	}
 	return result, nil
@@ -210,18 +215,19 @@ func (a *BitbucketProviderAdapter) ListPullRequests(owner, repo, state string) (
 	result := make([]PullRequest, len(prs))
 	for i, pr := range prs {
 		result[i] = PullRequest{
-			Provider:      "bitbucket",
-			ID:            strconv.Itoa(pr.ID),
-			Number:        pr.ID,
-			Title:         pr.Title,
-			Body:          pr.Description,
-			State:         strings.ToLower(pr.State),
-			URL:           pr.URL,
-			Author:        pr.Author.Username,
-			SourceBranch:  pr.Source.Branch.Name,
-			TargetBranch:  pr.Destination.Branch.Name,
-			CreatedAt:     pr.CreatedOn.Format(time.RFC3339),
-			UpdatedAt:     pr.UpdatedOn.Format(time.RFC3339),
+			Provider:     "bitbucket",
+			ID:           strconv.Itoa(pr.ID),
+			Number:       pr.ID,
+			Title:        pr.Title,
+			Body:         pr.Description,
+			State:        strings.ToLower(pr.State),
+			URL:          pr.Links.HTML.Href,
+			Author:       pr.Author.Username,
+			SourceBranch: pr.Source.Branch.Name,
+			TargetBranch: pr.Destination.Branch.Name,
+			CreatedAt:    pr.CreatedOn.Format(time.RFC3339),
+			UpdatedAt:    pr.UpdatedOn.Format(time.RFC3339),
+			MergedAt:     bitbucketMergedAt(pr),
 		}
 	}
 	return result, nil
@@ -235,18 +241,19 @@ func (a *BitbucketProviderAdapter) GetPullRequest(owner, repo string, number int
 	}
 
 	return &PullRequest{
-		Provider:      "bitbucket",
-		ID:            strconv.Itoa(pr.ID),
-		Number:        pr.ID,
-		Title:         pr.Title,
-		Body:          pr.Description,
-		State:         strings.ToLower(pr.State),
-		URL:           pr.URL,
-		Author:        pr.Author.Username,
-		SourceBranch:  pr.Source.Branch.Name,
-		TargetBranch:  pr.Destination.Branch.Name,
-		CreatedAt:     pr.CreatedOn.Format(time.RFC3339),
-		UpdatedAt:     pr.UpdatedOn.Format(time.RFC3339),
+		Provider:     "bitbucket",
+		ID:           strconv.Itoa(pr.ID),
+		Number:       pr.ID,
+		Title:        pr.Title,
+		Body:         pr.Description,
+		State:        strings.ToLower(pr.State),
+		URL:          pr.Links.HTML.Href,
+		Author:       pr.Author.Username,
+		SourceBranch: pr.Source.Branch.Name,
+		TargetBranch: pr.Destination.Branch.Name,
+		CreatedAt:    pr.CreatedOn.Format(time.RFC3339),
+		UpdatedAt:    pr.UpdatedOn.Format(time.RFC3339),
+		MergedAt:     bitbucketMergedAt(*pr),
 	}, nil
 }
 
@@ -260,18 +267,785 @@ func (a *BitbucketProviderAdapter) ListIssues(owner, repo, state string) ([]Issu
 	result := make([]Issue, len(issues))
 	for i, issue := range issues {
 		result[i] = Issue{
-			Provider:   "bitbucket",
-			ID:         strconv.Itoa(issue.ID),
-			Number:     issue.ID,
-			Title:      issue.Title,
-			Body:       issue.Content,
-			State:      strings.ToLower(issue.State),
-			URL:        fmt.Sprintf("https://bitbucket.org/%s/%s/issues/%d", owner, repo, issue.ID),
-			Author:     issue.Reporter.Username,
-			Labels:     []string{issue.Kind},
-			CreatedAt:  issue.CreatedOn.Format(time.RFC3339),
-			UpdatedAt:  issue.UpdatedOn.Format(time.RFC3339),
+			Provider:  "bitbucket",
+			ID:        strconv.Itoa(issue.ID),
+			Number:    issue.ID,
+			Title:     issue.Title,
+			Body:      issue.Content,
+			State:     strings.ToLower(issue.State),
+			URL:       fmt.Sprintf("https://bitbucket.org/%s/%s/issues/%d", owner, repo, issue.ID),
+			Author:    issue.Reporter.Username,
+			Labels:    []string{issue.Kind},
+			CreatedAt: issue.CreatedOn.Format(time.RFC3339),
+			UpdatedAt: issue.UpdatedOn.Format(time.RFC3339),
 		}
 	}
 	return result, nil
 }
+
+// BatchFetch fetches pull requests and issues for many repositories through
+// the GitHub GraphQL API
+func (a *GitHubProviderAdapter) BatchFetch(repos []RepoRef, opts BatchOptions) (map[RepoRef]*RepoItems, error) {
+	ctx := context.Background()
+	batches, err := a.client.BatchRepositories(ctx, repos, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[RepoRef]*RepoItems, len(batches))
+	for ref, batch := range batches {
+		items := &RepoItems{Err: batch.Err}
+		for _, pr := range batch.PullRequests {
+			items.PullRequests = append(items.PullRequests, githubGraphQLPullRequest(pr))
+		}
+		for _, issue := range batch.Issues {
+			items.Issues = append(items.Issues, githubGraphQLIssue(issue))
+		}
+		results[ref] = items
+	}
+	return results, nil
+}
+
+func githubGraphQLPullRequest(pr GitHubGraphQLPullRequest) PullRequest {
+	author := ""
+	if pr.Author != nil {
+		author = pr.Author.Login
+	}
+	// Merged PRs are reported as closed, matching the REST API
+	state := strings.ToLower(pr.State)
+	if state == "merged" {
+		state = "closed"
+	}
+	checkStatus := ""
+	if len(pr.Commits.Nodes) > 0 && pr.Commits.Nodes[0].Commit.StatusCheckRollup != nil {
+		checkStatus = strings.ToLower(pr.Commits.Nodes[0].Commit.StatusCheckRollup.State)
+	}
+
+	return PullRequest{
+		Provider:     "github",
+		ID:           strconv.Itoa(pr.DatabaseID),
+		Number:       pr.Number,
+		Title:        pr.Title,
+		Body:         pr.Body,
+		State:        state,
+		URL:          pr.URL,
+		Author:       author,
+		SourceBranch: pr.HeadRefName,
+		TargetBranch: pr.BaseRefName,
+		ReviewState:  strings.ToLower(pr.ReviewDecision),
+		CheckStatus:  checkStatus,
+		CreatedAt:    pr.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:    pr.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func githubGraphQLIssue(issue GitHubGraphQLIssue) Issue {
+	author := ""
+	if issue.Author != nil {
+		author = issue.Author.Login
+	}
+	labels := make([]string, len(issue.Labels.Nodes))
+	for i, label := range issue.Labels.Nodes {
+		labels[i] = label.Name
+	}
+
+	return Issue{
+		Provider:  "github",
+		ID:        strconv.Itoa(issue.DatabaseID),
+		Number:    issue.Number,
+		Title:     issue.Title,
+		Body:      issue.Body,
+		State:     strings.ToLower(issue.State),
+		URL:       issue.URL,
+		Author:    author,
+		Labels:    labels,
+		CreatedAt: issue.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: issue.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// SearchPullRequests searches pull requests through the GitHub search API
+func (a *GitHubProviderAdapter) SearchPullRequests(q SearchQuery) ([]PullRequest, error) {
+	ctx := context.Background()
+	items, err := a.client.SearchIssues(ctx, githubSearchQuery(q, "pr"))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]PullRequest, len(items))
+	for i, item := range items {
+		result[i] = PullRequest{
+			Provider:  "github",
+			Repo:      githubRepoFromAPIURL(item.RepositoryURL),
+			ID:        strconv.Itoa(item.ID),
+			Number:    item.Number,
+			Title:     item.Title,
+			Body:      item.Body,
+			State:     item.State,
+			URL:       item.URL,
+			Author:    item.User.Login,
+			CreatedAt: item.CreatedAt.Format(time.RFC3339),
+			UpdatedAt: item.UpdatedAt.Format(time.RFC3339),
+		}
+	}
+	return result, nil
+}
+
+// SearchIssues searches issues through the GitHub search API
+func (a *GitHubProviderAdapter) SearchIssues(q SearchQuery) ([]Issue, error) {
+	ctx := context.Background()
+	items, err := a.client.SearchIssues(ctx, githubSearchQuery(q, "issue"))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Issue, len(items))
+	for i, item := range items {
+		labels := make([]string, len(item.Labels))
+		for j, label := range item.Labels {
+			labels[j] = label.Name
+		}
+		result[i] = Issue{
+			Provider:  "github",
+			Repo:      githubRepoFromAPIURL(item.RepositoryURL),
+			ID:        strconv.Itoa(item.ID),
+			Number:    item.Number,
+			Title:     item.Title,
+			Body:      item.Body,
+			State:     item.State,
+			URL:       item.URL,
+			Author:    item.User.Login,
+			Labels:    labels,
+			CreatedAt: item.CreatedAt.Format(time.RFC3339),
+			UpdatedAt: item.UpdatedAt.Format(time.RFC3339),
+		}
+	}
+	return result, nil
+}
+
+// githubRepoFromAPIURL turns https://api.github.com/repos/owner/name into owner/name
+func githubRepoFromAPIURL(apiURL string) string {
+	if i := strings.Index(apiURL, "/repos/"); i >= 0 {
+		return apiURL[i+len("/repos/"):]
+	}
+	return ""
+}
+
+// SearchPullRequests searches merge requests through the GitLab list APIs
+func (a *GitLabProviderAdapter) SearchPullRequests(q SearchQuery) ([]PullRequest, error) {
+	ctx := context.Background()
+	mrs, err := a.client.SearchMergeRequests(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]PullRequest, len(mrs))
+	for i, mr := range mrs {
+		result[i] = PullRequest{
+			Provider:     "gitlab",
+			Repo:         repoFromWebURL(mr.URL),
+			ID:           strconv.Itoa(mr.ID),
+			Number:       mr.IID,
+			Title:        mr.Title,
+			Body:         mr.Description,
+			State:        mr.State,
+			URL:          mr.URL,
+			Author:       mr.Author.Username,
+			SourceBranch: mr.SourceBranch,
+			TargetBranch: mr.TargetBranch,
+			CreatedAt:    mr.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:    mr.UpdatedAt.Format(time.RFC3339),
+		}
+	}
+	return result, nil
+}
+
+// SearchIssues searches issues through the GitLab list APIs
+func (a *GitLabProviderAdapter) SearchIssues(q SearchQuery) ([]Issue, error) {
+	ctx := context.Background()
+	issues, err := a.client.SearchIssues(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Issue, len(issues))
+	for i, issue := range issues {
+		result[i] = Issue{
+			Provider:  "gitlab",
+			Repo:      repoFromWebURL(issue.URL),
+			ID:        strconv.Itoa(issue.ID),
+			Number:    issue.IID,
+			Title:     issue.Title,
+			Body:      issue.Description,
+			State:     issue.State,
+			URL:       issue.URL,
+			Author:    issue.Author.Username,
+			Labels:    issue.Labels,
+			CreatedAt: issue.CreatedAt.Format(time.RFC3339),
+			UpdatedAt: issue.UpdatedAt.Format(time.RFC3339),
+		}
+	}
+	return result, nil
+}
+
+// SearchPullRequests searches pull requests with a BBQL filter
+func (a *BitbucketProviderAdapter) SearchPullRequests(q SearchQuery) ([]PullRequest, error) {
+	ctx := context.Background()
+	prs, err := a.client.SearchPullRequests(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]PullRequest, len(prs))
+	for i, pr := range prs {
+		result[i] = PullRequest{
+			Provider:     "bitbucket",
+			Repo:         pr.Destination.Repository.FullName,
+			ID:           strconv.Itoa(pr.ID),
+			Number:       pr.ID,
+			Title:        pr.Title,
+			Body:         pr.Description,
+			State:        strings.ToLower(pr.State),
+			URL:          pr.Links.HTML.Href,
+			Author:       pr.Author.Username,
+			SourceBranch: pr.Source.Branch.Name,
+			TargetBranch: pr.Destination.Branch.Name,
+			CreatedAt:    pr.CreatedOn.Format(time.RFC3339),
+			UpdatedAt:    pr.UpdatedOn.Format(time.RFC3339),
+		}
+	}
+	return result, nil
+}
+
+// SearchIssues searches issues with a BBQL filter
+func (a *BitbucketProviderAdapter) SearchIssues(q SearchQuery) ([]Issue, error) {
+	ctx := context.Background()
+	byRepo, err := a.client.SearchIssues(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Issue
+	for repo, issues := range byRepo {
+		for _, issue := range issues {
+			result = append(result, Issue{
+				Provider:  "bitbucket",
+				Repo:      repo,
+				ID:        strconv.Itoa(issue.ID),
+				Number:    issue.ID,
+				Title:     issue.Title,
+				Body:      issue.Content,
+				State:     strings.ToLower(issue.State),
+				URL:       fmt.Sprintf("https://bitbucket.org/%s/issues/%d", repo, issue.ID),
+				Author:    issue.Reporter.Username,
+				Labels:    []string{issue.Kind},
+				CreatedAt: issue.CreatedOn.Format(time.RFC3339),
+				UpdatedAt: issue.UpdatedOn.Format(time.RFC3339),
+			})
+		}
+	}
+	return result, nil
+}
+
+// ListNotifications lists GitHub notifications
+func (a *GitHubProviderAdapter) ListNotifications(opts NotificationOptions) ([]Notification, error) {
+	ctx := context.Background()
+	threads, err := a.client.ListNotifications(ctx, opts.All)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Notification, len(threads))
+	for i, thread := range threads {
+		result[i] = Notification{
+			Provider:  "github",
+			ID:        thread.ID,
+			Reason:    githubReason(thread.Reason),
+			Type:      notificationType(thread.Subject.Type),
+			Title:     thread.Subject.Title,
+			Repo:      thread.Repository.FullName,
+			URL:       githubHTMLURL(thread.Subject.URL),
+			UpdatedAt: thread.UpdatedAt.Format(time.RFC3339),
+			Unread:    thread.Unread,
+		}
+	}
+	return result, nil
+}
+
+// MarkNotificationRead marks a GitHub notification thread as read
+func (a *GitHubProviderAdapter) MarkNotificationRead(id string) error {
+	return a.client.MarkThreadRead(context.Background(), id)
+}
+
+// MarkNotificationDone marks a GitHub notification thread as done
+func (a *GitHubProviderAdapter) MarkNotificationDone(id string) error {
+	return a.client.MarkThreadDone(context.Background(), id)
+}
+
+// ListNotifications lists GitLab to-do items
+func (a *GitLabProviderAdapter) ListNotifications(opts NotificationOptions) ([]Notification, error) {
+	ctx := context.Background()
+	todos, err := a.client.ListTodos(ctx, opts.All)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Notification, len(todos))
+	for i, todo := range todos {
+		result[i] = Notification{
+			Provider:  "gitlab",
+			ID:        strconv.Itoa(todo.ID),
+			Reason:    gitlabReason(todo.ActionName),
+			Type:      notificationType(todo.TargetType),
+			Title:     todo.Target.Title,
+			Repo:      todo.Project.PathWithNamespace,
+			URL:       todo.TargetURL,
+			UpdatedAt: todo.CreatedAt.Format(time.RFC3339),
+			Unread:    todo.State == "pending",
+		}
+	}
+	return result, nil
+}
+
+// MarkNotificationRead is not supported: GitLab to-dos have no read state,
+// only done, so reading is tracked locally
+func (a *GitLabProviderAdapter) MarkNotificationRead(id string) error {
+	return ErrNotSupported
+}
+
+// MarkNotificationDone marks a GitLab to-do as done
+func (a *GitLabProviderAdapter) MarkNotificationDone(id string) error {
+	return a.client.MarkTodoDone(context.Background(), id)
+}
+
+// ListNotifications reports activity on the user's own open pull requests
+// and review requests in opts.Repos. Bitbucket has no notifications API, so
+// read state is left to the caller.
+func (a *BitbucketProviderAdapter) ListNotifications(opts NotificationOptions) ([]Notification, error) {
+	ctx := context.Background()
+	user, err := a.client.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	authored, err := a.client.ListUserPullRequests(ctx, user.UUID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Notification
+	for _, pr := range authored {
+		result = append(result, bitbucketNotification(pr, ReasonAuthor))
+	}
+
+	for _, repo := range opts.Repos {
+		prs, err := a.client.ListReviewRequests(ctx, repo.String(), user.UUID)
+		if err != nil {
+			return nil, err
+		}
+		for _, pr := range prs {
+			result = append(result, bitbucketNotification(pr, ReasonReviewRequested))
+		}
+	}
+
+	return result, nil
+}
+
+// MarkNotificationRead is not supported by Bitbucket
+func (a *BitbucketProviderAdapter) MarkNotificationRead(id string) error {
+	return ErrNotSupported
+}
+
+// MarkNotificationDone is not supported by Bitbucket
+func (a *BitbucketProviderAdapter) MarkNotificationDone(id string) error {
+	return ErrNotSupported
+}
+
+func bitbucketNotification(pr BitbucketPullRequest, reason string) Notification {
+	repo := pr.Destination.Repository.FullName
+	return Notification{
+		Provider:  "bitbucket",
+		ID:        fmt.Sprintf("%s#%d", repo, pr.ID),
+		Reason:    reason,
+		Type:      "pr",
+		Title:     pr.Title,
+		Repo:      repo,
+		URL:       pr.Links.HTML.Href,
+		UpdatedAt: pr.UpdatedOn.Format(time.RFC3339),
+		Unread:    true,
+	}
+}
+
+// formatOptionalTime formats t as RFC3339, or returns "" when t is nil
+func formatOptionalTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// bitbucketMergedAt approximates the merge time of a Bitbucket pull request
+// with its last update, since the API does not expose one directly
+func bitbucketMergedAt(pr BitbucketPullRequest) string {
+	if pr.State != "MERGED" {
+		return ""
+	}
+	return pr.UpdatedOn.Format(time.RFC3339)
+}
+
+// ListMergedPullRequests lists the pull requests merged after since
+func (a *GitHubProviderAdapter) ListMergedPullRequests(owner, repo string, since time.Time) ([]PullRequest, error) {
+	ctx := context.Background()
+	items, err := a.client.ListMergedPullRequests(ctx, owner, repo, since)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]PullRequest, len(items))
+	for i, item := range items {
+		result[i] = PullRequest{
+			Provider:  "github",
+			Repo:      owner + "/" + repo,
+			ID:        strconv.Itoa(item.ID),
+			Number:    item.Number,
+			Title:     item.Title,
+			Body:      item.Body,
+			State:     item.State,
+			URL:       item.URL,
+			Author:    item.User.Login,
+			CreatedAt: item.CreatedAt.Format(time.RFC3339),
+			UpdatedAt: item.UpdatedAt.Format(time.RFC3339),
+		}
+		if item.PullRequest != nil {
+			result[i].MergedAt = formatOptionalTime(item.PullRequest.MergedAt)
+		}
+	}
+	return result, nil
+}
+
+// ListMergedPullRequests lists the merge requests merged after since
+func (a *GitLabProviderAdapter) ListMergedPullRequests(owner, repo string, since time.Time) ([]PullRequest, error) {
+	ctx := context.Background()
+	mrs, err := a.client.ListMergedMergeRequests(ctx, owner+"/"+repo, since)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]PullRequest, len(mrs))
+	for i, mr := range mrs {
+		result[i] = PullRequest{
+			Provider:     "gitlab",
+			Repo:         owner + "/" + repo,
+			ID:           strconv.Itoa(mr.ID),
+			Number:       mr.IID,
+			Title:        mr.Title,
+			Body:         mr.Description,
+			State:        mr.State,
+			URL:          mr.URL,
+			Author:       mr.Author.Username,
+			SourceBranch: mr.SourceBranch,
+			TargetBranch: mr.TargetBranch,
+			CreatedAt:    mr.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:    mr.UpdatedAt.Format(time.RFC3339),
+			MergedAt:     formatOptionalTime(mr.MergedAt),
+		}
+	}
+	return result, nil
+}
+
+// ListMergedPullRequests lists the pull requests merged after since
+func (a *BitbucketProviderAdapter) ListMergedPullRequests(owner, repo string, since time.Time) ([]PullRequest, error) {
+	ctx := context.Background()
+	prs, err := a.client.ListMergedPullRequests(ctx, owner, repo, since)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]PullRequest, len(prs))
+	for i, pr := range prs {
+		result[i] = PullRequest{
+			Provider:     "bitbucket",
+			Repo:         owner + "/" + repo,
+			ID:           strconv.Itoa(pr.ID),
+			Number:       pr.ID,
+			Title:        pr.Title,
+			Body:         pr.Description,
+			State:        strings.ToLower(pr.State),
+			URL:          pr.Links.HTML.Href,
+			Author:       pr.Author.Username,
+			SourceBranch: pr.Source.Branch.Name,
+			TargetBranch: pr.Destination.Branch.Name,
+			CreatedAt:    pr.CreatedOn.Format(time.RFC3339),
+			UpdatedAt:    pr.UpdatedOn.Format(time.RFC3339),
+			MergedAt:     bitbucketMergedAt(pr),
+		}
+	}
+	return result, nil
+}
+
+// ListReleases lists GitHub releases
+func (a *GitHubProviderAdapter) ListReleases(owner, repo string) ([]Release, error) {
+	ctx := context.Background()
+	releases, err := a.client.ListReleases(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Release, len(releases))
+	for i, r := range releases {
+		result[i] = githubRelease(owner, repo, r)
+	}
+	return result, nil
+}
+
+// GetRelease gets the GitHub release for a tag
+func (a *GitHubProviderAdapter) GetRelease(owner, repo, tag string) (*Release, error) {
+	ctx := context.Background()
+	r, err := a.client.GetReleaseByTag(ctx, owner, repo, tag)
+	if err != nil {
+		return nil, err
+	}
+	release := githubRelease(owner, repo, *r)
+	return &release, nil
+}
+
+// CreateRelease creates a GitHub release and uploads its assets
+func (a *GitHubProviderAdapter) CreateRelease(owner, repo string, opts ReleaseOptions) (*Release, error) {
+	ctx := context.Background()
+	r, err := a.client.CreateRelease(ctx, owner, repo, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range opts.Assets {
+		asset, err := a.client.UploadReleaseAsset(ctx, r.UploadURL, path)
+		if err != nil {
+			return nil, fmt.Errorf("release created but uploading %s failed: %w", path, err)
+		}
+		r.Assets = append(r.Assets, *asset)
+	}
+
+	release := githubRelease(owner, repo, *r)
+	return &release, nil
+}
+
+func githubRelease(owner, repo string, r GitHubRelease) Release {
+	assets := make([]ReleaseAsset, len(r.Assets))
+	for i, asset := range r.Assets {
+		assets[i] = ReleaseAsset{Name: asset.Name, URL: asset.DownloadURL, Size: asset.Size}
+	}
+	return Release{
+		Provider:    "github",
+		Repo:        owner + "/" + repo,
+		TagName:     r.TagName,
+		Name:        r.Name,
+		Body:        r.Body,
+		URL:         r.URL,
+		Author:      r.Author.Login,
+		Target:      r.TargetCommitish,
+		Draft:       r.Draft,
+		Prerelease:  r.Prerelease,
+		CreatedAt:   r.CreatedAt.Format(time.RFC3339),
+		PublishedAt: formatOptionalTime(r.PublishedAt),
+		Assets:      assets,
+	}
+}
+
+// ListReleases lists GitLab releases
+func (a *GitLabProviderAdapter) ListReleases(owner, repo string) ([]Release, error) {
+	ctx := context.Background()
+	releases, err := a.client.ListReleases(ctx, owner+"/"+repo)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Release, len(releases))
+	for i, r := range releases {
+		result[i] = gitlabRelease(owner, repo, r)
+	}
+	return result, nil
+}
+
+// GetRelease gets the GitLab release for a tag
+func (a *GitLabProviderAdapter) GetRelease(owner, repo, tag string) (*Release, error) {
+	ctx := context.Background()
+	r, err := a.client.GetRelease(ctx, owner+"/"+repo, tag)
+	if err != nil {
+		return nil, err
+	}
+	release := gitlabRelease(owner, repo, *r)
+	return &release, nil
+}
+
+// CreateRelease creates a GitLab release and links its assets. GitLab has
+// no draft or prerelease state.
+func (a *GitLabProviderAdapter) CreateRelease(owner, repo string, opts ReleaseOptions) (*Release, error) {
+	if opts.Draft || opts.Prerelease {
+		return nil, fmt.Errorf("GitLab releases do not support draft or prerelease")
+	}
+
+	ctx := context.Background()
+	projectID := owner + "/" + repo
+	r, err := a.client.CreateRelease(ctx, projectID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	release := gitlabRelease(owner, repo, *r)
+	for _, path := range opts.Assets {
+		assetURL, err := a.client.UploadReleaseAsset(ctx, projectID, opts.TagName, path)
+		if err != nil {
+			return nil, fmt.Errorf("release created but uploading %s failed: %w", path, err)
+		}
+		release.Assets = append(release.Assets, ReleaseAsset{Name: filepath.Base(path), URL: assetURL})
+	}
+	return &release, nil
+}
+
+func gitlabRelease(owner, repo string, r GitLabRelease) Release {
+	assets := make([]ReleaseAsset, len(r.Assets.Links))
+	for i, link := range r.Assets.Links {
+		assets[i] = ReleaseAsset{Name: link.Name, URL: link.URL}
+	}
+	return Release{
+		Provider:    "gitlab",
+		Repo:        owner + "/" + repo,
+		TagName:     r.TagName,
+		Name:        r.Name,
+		Body:        r.Description,
+		URL:         r.Links.Self,
+		Author:      r.Author.Username,
+		Target:      r.Commit.ID,
+		CreatedAt:   r.CreatedAt.Format(time.RFC3339),
+		PublishedAt: formatOptionalTime(r.ReleasedAt),
+		Assets:      assets,
+	}
+}
+
+// ListReleases lists Bitbucket tags as releases
+func (a *BitbucketProviderAdapter) ListReleases(owner, repo string) ([]Release, error) {
+	ctx := context.Background()
+	tags, err := a.client.ListTags(ctx, owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Release, len(tags))
+	for i, tag := range tags {
+		result[i] = bitbucketRelease(owner, repo, tag)
+	}
+	return result, nil
+}
+
+// GetRelease gets a Bitbucket tag as a release
+func (a *BitbucketProviderAdapter) GetRelease(owner, repo, tag string) (*Release, error) {
+	ctx := context.Background()
+	t, err := a.client.GetTag(ctx, owner, repo, tag)
+	if err != nil {
+		return nil, err
+	}
+	release := bitbucketRelease(owner, repo, *t)
+	return &release, nil
+}
+
+// CreateRelease creates a Bitbucket tag and uploads assets to the
+// repository's downloads. opts.Target must be a commit hash.
+func (a *BitbucketProviderAdapter) CreateRelease(owner, repo string, opts ReleaseOptions) (*Release, error) {
+	if opts.Draft || opts.Prerelease {
+		return nil, fmt.Errorf("Bitbucket tags do not support draft or prerelease")
+	}
+	if opts.Target == "" {
+		return nil, fmt.Errorf("Bitbucket requires a target commit hash")
+	}
+
+	ctx := context.Background()
+	message := opts.Body
+	if opts.Name != "" {
+		message = strings.TrimSpace(opts.Name + "\n\n" + opts.Body)
+	}
+	t, err := a.client.CreateTag(ctx, owner, repo, opts.TagName, opts.Target, message)
+	if err != nil {
+		return nil, err
+	}
+
+	release := bitbucketRelease(owner, repo, *t)
+	for _, path := range opts.Assets {
+		assetURL, err := a.client.UploadDownload(ctx, owner, repo, path)
+		if err != nil {
+			return nil, fmt.Errorf("tag created but uploading %s failed: %w", path, err)
+		}
+		release.Assets = append(release.Assets, ReleaseAsset{Name: filepath.Base(path), URL: assetURL})
+	}
+	return &release, nil
+}
+
+func bitbucketRelease(owner, repo string, t BitbucketTag) Release {
+	createdAt := t.Target.Date
+	if t.Date != nil {
+		createdAt = *t.Date
+	}
+	author := ""
+	if t.Tagger != nil {
+		author = t.Tagger.Raw
+	}
+	return Release{
+		Provider:    "bitbucket",
+		Repo:        owner + "/" + repo,
+		TagName:     t.Name,
+		Name:        t.Name,
+		Body:        t.Message,
+		URL:         t.Links.HTML.Href,
+		Author:      author,
+		Target:      t.Target.Hash,
+		CreatedAt:   createdAt.Format(time.RFC3339),
+		PublishedAt: createdAt.Format(time.RFC3339),
+	}
+}
+
+// Validate checks the GitHub token and reports the account it belongs to
+func (a *GitHubProviderAdapter) Validate() (*Identity, error) {
+	user, info, err := a.client.GetAuthenticatedUser(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return &Identity{
+		Provider:  "github",
+		Login:     user.Login,
+		Name:      user.Name,
+		Scopes:    info.Scopes,
+		ExpiresAt: info.ExpiresAt,
+	}, nil
+}
+
+// Validate checks the GitLab token and reports the account it belongs to
+func (a *GitLabProviderAdapter) Validate() (*Identity, error) {
+	ctx := context.Background()
+	user, err := a.client.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id := &Identity{Provider: "gitlab", Login: user.Username, Name: user.Name}
+	// Scopes are only available for personal access tokens
+	if info, err := a.client.GetTokenInfo(ctx); err == nil {
+		id.Scopes = info.Scopes
+		id.ExpiresAt = info.ExpiresAt
+	}
+	return id, nil
+}
+
+// Validate checks the Bitbucket credentials and reports the account they
+// belong to
+func (a *BitbucketProviderAdapter) Validate() (*Identity, error) {
+	ctx := context.Background()
+	user, err := a.client.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id := &Identity{Provider: "bitbucket", Login: user.Nickname, Name: user.DisplayName}
+	if info, err := a.client.GetTokenInfo(ctx); err == nil {
+		id.Scopes = info.Scopes
+	}
+	return id, nil
+}
@@ -0,0 +1,173 @@
+package api
+
+import "fmt"
+
+// RepoRef identifies a repository on a provider
+type RepoRef struct {
+	Owner string
+	Name  string
+}
+
+// String returns the owner/name form of the reference
+func (r RepoRef) String() string {
+	return r.Owner + "/" + r.Name
+}
+
+// RepoItems holds the pull requests and issues fetched for one repository.
+// Err records the first failure; whatever was fetched successfully is kept.
+type RepoItems struct {
+	PullRequests []PullRequest
+	Issues       []Issue
+	Err          error
+}
+
+// DefaultBatchLimit is how many pull requests and issues are fetched per
+// repository when BatchOptions.Limit isn't set
+const DefaultBatchLimit = 100
+
+// BatchOptions controls what FetchRepoItems retrieves
+type BatchOptions struct {
+	State        string // open, closed, all
+	PullRequests bool
+	Issues       bool
+	// Limit caps the pull requests and the issues fetched per repository,
+	// most recently updated first; 0 means DefaultBatchLimit
+	Limit int
+}
+
+// limit returns the per-repository cap, applying the default
+func (o BatchOptions) limit() int {
+	if o.Limit <= 0 {
+		return DefaultBatchLimit
+	}
+	return o.Limit
+}
+
+// BatchProvider is implemented by providers that can query many
+// repositories in a single request
+type BatchProvider interface {
+	Provider
+	BatchFetch(repos []RepoRef, opts BatchOptions) (map[RepoRef]*RepoItems, error)
+}
+
+// FetchRepoItems fetches pull requests and/or issues for several repositories
+// on the same provider. Providers implementing BatchProvider are queried in
+// bulk; all others fall back to one REST call per repository and item type,
+// whose results are cut to the limit the same way.
+func FetchRepoItems(p Provider, repos []RepoRef, opts BatchOptions) map[RepoRef]*RepoItems {
+	if bp, ok := p.(BatchProvider); ok {
+		results, err := bp.BatchFetch(repos, opts)
+		if err == nil {
+			return results
+		}
+		// The batch request failed as a whole; report it for every repo
+		failed := make(map[RepoRef]*RepoItems, len(repos))
+		for _, repo := range repos {
+			failed[repo] = &RepoItems{Err: err}
+		}
+		return failed
+	}
+
+	results := make(map[RepoRef]*RepoItems, len(repos))
+	for _, repo := range repos {
+		items := &RepoItems{}
+		if opts.PullRequests {
+			prs, err := p.ListPullRequests(repo.Owner, repo.Name, opts.State)
+			if err != nil {
+				items.Err = err
+			}
+			SortPullRequestsByUpdated(prs)
+			if len(prs) > opts.limit() {
+				prs = prs[:opts.limit()]
+			}
+			items.PullRequests = prs
+		}
+		if opts.Issues {
+			issues, err := p.ListIssues(repo.Owner, repo.Name, opts.State)
+			if err != nil && items.Err == nil {
+				items.Err = err
+			}
+			SortIssuesByUpdated(issues)
+			if len(issues) > opts.limit() {
+				issues = issues[:opts.limit()]
+			}
+			items.Issues = issues
+		}
+		results[repo] = items
+	}
+	return results
+}
+
+// RemoteResult is the outcome of fetching items for one remote URL
+type RemoteResult struct {
+	Remote   string
+	Provider string
+	Repo     RepoRef
+	Items    *RepoItems
+}
+
+// FetchRemotes parses each remote URL, groups the repositories by the
+// account the routes select for them and fetches their items with
+// FetchRepoItems. Results are returned in the same order as remotes; parse
+// and configuration errors are reported in Items.Err.
+func (f *ProviderFactory) FetchRemotes(remotes []string, opts BatchOptions) []RemoteResult {
+	results := make([]RemoteResult, len(remotes))
+	accounts := make([]string, len(remotes))
+	grouped := make(map[string][]RepoRef)
+	var order []string
+
+	for i, remote := range remotes {
+		results[i].Remote = remote
+		providerName, owner, name, err := ParseRepoURL(remote)
+		if err != nil {
+			results[i].Items = &RepoItems{Err: err}
+			continue
+		}
+		ref := RepoRef{Owner: owner, Name: name}
+		results[i].Provider = providerName
+		results[i].Repo = ref
+
+		account := f.RouteAccount(providerName, RemoteHost(remote), owner, name)
+		accounts[i] = account
+		if _, ok := grouped[account]; !ok {
+			order = append(order, account)
+		}
+		grouped[account] = append(grouped[account], ref)
+	}
+
+	fetched := make(map[string]map[RepoRef]*RepoItems)
+	accountErrs := make(map[string]error)
+	for _, account := range order {
+		providerName, accountName := SplitAccountKey(account)
+		factory := f
+		if accountName != "" {
+			factory = f.Account(account)
+		}
+		provider, err := factory.GetProvider(providerName)
+		if err != nil {
+			if accountName != "" {
+				err = fmt.Errorf("account %s: %w", account, err)
+			}
+			accountErrs[account] = err
+			continue
+		}
+		fetched[account] = FetchRepoItems(provider, grouped[account], opts)
+	}
+
+	for i := range results {
+		if results[i].Items != nil {
+			continue
+		}
+		if err, ok := accountErrs[accounts[i]]; ok {
+			results[i].Items = &RepoItems{Err: err}
+			continue
+		}
+		items, ok := fetched[accounts[i]][results[i].Repo]
+		if !ok {
+			items = &RepoItems{}
+		}
+		results[i].Items = items
+	}
+
+	return results
+}
@@ -8,6 +8,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -76,13 +79,13 @@ func (c *BitbucketClient) doRequest(ctx context.Context, method, path string, bo
 
 // BitbucketPullRequest represents a Bitbucket pull request
 type BitbucketPullRequest struct {
-	ID          int       `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	State       string    `json:"state"`
-	URL         string    `json:"links.html.href"`
-	CreatedOn   time.Time `json:"created_on"`
-	UpdatedOn   time.Time `json:"updated_on"`
+	ID          int            `json:"id"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	State       string         `json:"state"`
+	Links       BitbucketLinks `json:"links"`
+	CreatedOn   time.Time      `json:"created_on"`
+	UpdatedOn   time.Time      `json:"updated_on"`
 	Author      struct {
 		DisplayName string `json:"display_name"`
 		Username    string `json:"username"`
@@ -96,19 +99,35 @@ type BitbucketPullRequest struct {
 		Branch struct {
 			Name string `json:"name"`
 		} `json:"branch"`
+		Repository struct {
+			FullName string `json:"full_name"`
+		} `json:"repository"`
 	} `json:"destination"`
 }
 
+// BitbucketLinks represents the links object on Bitbucket resources
+type BitbucketLinks struct {
+	HTML struct {
+		Href string `json:"href"`
+	} `json:"html"`
+}
+
+// BitbucketRepository represents a Bitbucket repository
+type BitbucketRepository struct {
+	Slug     string `json:"slug"`
+	FullName string `json:"full_name"`
+}
+
 // BitbucketIssue represents a Bitbucket issue
 type BitbucketIssue struct {
-	ID          int       `json:"id"`
-	Title       string    `json:"title"`
-	Content     string    `json:"content.raw"`
-	State       string    `json:"state"`
-	Kind        string    `json:"kind"`
-	CreatedOn   time.Time `json:"created_on"`
-	UpdatedOn   time.Time `json:"updated_on"`
-	Reporter    struct {
+	ID        int       `json:"id"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content.raw"`
+	State     string    `json:"state"`
+	Kind      string    `json:"kind"`
+	CreatedOn time.Time `json:"created_on"`
+	UpdatedOn time.Time `json:"updated_on"`
+	Reporter  struct {
 		DisplayName string `json:"display_name"`
 		Username    string `json:"username"`
 	} `json:"reporter"`
@@ -137,6 +156,45 @@ func (c *BitbucketClient) ListPullRequests(ctx context.Context, workspace, repo
 	return result.Values, nil
 }
 
+// ListMergedPullRequests lists the pull requests of a repository merged
+// after since, following the next links until they run out. Bitbucket has no
+// merge time, so the last update is used, as it is elsewhere. A zero since
+// lists every merged pull request.
+func (c *BitbucketClient) ListMergedPullRequests(ctx context.Context, workspace, repo string, since time.Time) ([]BitbucketPullRequest, error) {
+	filter := `state = "MERGED"`
+	if !since.IsZero() {
+		filter += " AND updated_on > " + since.UTC().Format(time.RFC3339)
+	}
+	path := fmt.Sprintf("/repositories/%s/%s/pullrequests?state=MERGED&pagelen=%d&sort=-updated_on&q=%s",
+		workspace, repo, bitbucketMergedPageSize, url.QueryEscape(filter))
+
+	var prs []BitbucketPullRequest
+	for path != "" && len(prs) < maxMergedPullRequests {
+		resp, err := c.doRequest(ctx, "GET", path, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result struct {
+			Values []BitbucketPullRequest `json:"values"`
+			Next   string                 `json:"next"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		prs = append(prs, result.Values...)
+		path = ""
+		if next, ok := strings.CutPrefix(result.Next, c.baseURL); ok {
+			path = next
+		}
+	}
+
+	return prs, nil
+}
+
 // GetPullRequest gets a specific pull request
 func (c *BitbucketClient) GetPullRequest(ctx context.Context, workspace, repo string, id int) (*BitbucketPullRequest, error) {
 	path := fmt.Sprintf("/repositories/%s/%s/pullrequests/%d", workspace, repo, id)
@@ -176,3 +234,285 @@ func (c *BitbucketClient) ListIssues(ctx context.Context, workspace, repo string
 
 	return result.Values, nil
 }
+
+// ListRepositories lists the repositories in a workspace
+func (c *BitbucketClient) ListRepositories(ctx context.Context, workspace string) ([]BitbucketRepository, error) {
+	path := fmt.Sprintf("/repositories/%s?pagelen=100", workspace)
+	resp, err := c.doRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Values []BitbucketRepository `json:"values"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Values, nil
+}
+
+// SearchPullRequests searches pull requests matching q. Bitbucket has no
+// cross-repository search, so q must name a repository or a workspace; for a
+// workspace every repository in it is queried. Bitbucket pull requests have
+// no labels, so q can't filter by them.
+func (c *BitbucketClient) SearchPullRequests(ctx context.Context, q SearchQuery) ([]BitbucketPullRequest, error) {
+	if len(q.Labels) > 0 {
+		return nil, fmt.Errorf("Bitbucket pull requests have no labels; filtering by label is not supported")
+	}
+	repos, err := c.searchScope(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bitbucketSearchFilter(q, "pr")
+	var prs []BitbucketPullRequest
+	for _, repo := range repos {
+		path := fmt.Sprintf("/repositories/%s/pullrequests?pagelen=50&q=%s", repo, url.QueryEscape(filter))
+		resp, err := c.doRequest(ctx, "GET", path, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result struct {
+			Values []BitbucketPullRequest `json:"values"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		prs = append(prs, result.Values...)
+	}
+
+	return prs, nil
+}
+
+// SearchIssues searches issues matching q. The scope rules are the same as
+// for SearchPullRequests. The returned map gives the repository of each issue.
+func (c *BitbucketClient) SearchIssues(ctx context.Context, q SearchQuery) (map[string][]BitbucketIssue, error) {
+	repos, err := c.searchScope(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bitbucketSearchFilter(q, "issue")
+	issues := make(map[string][]BitbucketIssue)
+	for _, repo := range repos {
+		path := fmt.Sprintf("/repositories/%s/issues?pagelen=50&q=%s", repo, url.QueryEscape(filter))
+		resp, err := c.doRequest(ctx, "GET", path, nil)
+		if err != nil {
+			// Repositories without an issue tracker return 404
+			if strings.Contains(err.Error(), "(404)") && q.Repo == "" {
+				continue
+			}
+			return nil, err
+		}
+
+		var result struct {
+			Values []BitbucketIssue `json:"values"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		issues[repo] = append(issues[repo], result.Values...)
+	}
+
+	return issues, nil
+}
+
+// searchScope returns the full names of the repositories a search covers
+func (c *BitbucketClient) searchScope(ctx context.Context, q SearchQuery) ([]string, error) {
+	if q.Repo != "" {
+		return []string{q.Repo}, nil
+	}
+	if q.Org == "" {
+		return nil, fmt.Errorf("Bitbucket search requires --repo or --org")
+	}
+
+	repos, err := c.ListRepositories(ctx, q.Org)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(repos))
+	for i, repo := range repos {
+		names[i] = repo.FullName
+	}
+	return names, nil
+}
+
+// BitbucketUser represents a Bitbucket account
+type BitbucketUser struct {
+	UUID        string `json:"uuid"`
+	Nickname    string `json:"nickname"`
+	DisplayName string `json:"display_name"`
+}
+
+// GetCurrentUser returns the authenticated user
+func (c *BitbucketClient) GetCurrentUser(ctx context.Context) (*BitbucketUser, error) {
+	resp, err := c.doRequest(ctx, "GET", "/user", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var user BitbucketUser
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &user, nil
+}
+
+// GetTokenInfo returns the scopes granted to the credentials in use
+func (c *BitbucketClient) GetTokenInfo(ctx context.Context) (*TokenInfo, error) {
+	resp, err := c.doRequest(ctx, "GET", "/user", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return &TokenInfo{Scopes: scopesFromHeader(resp.Header, "X-OAuth-Scopes")}, nil
+}
+
+// ListUserPullRequests lists open pull requests authored by a user
+func (c *BitbucketClient) ListUserPullRequests(ctx context.Context, userUUID string) ([]BitbucketPullRequest, error) {
+	path := fmt.Sprintf("/pullrequests/%s?state=OPEN&pagelen=50", url.PathEscape(userUUID))
+	return c.listPullRequests(ctx, path)
+}
+
+// ListReviewRequests lists open pull requests in a repository that have the
+// given user as a reviewer
+func (c *BitbucketClient) ListReviewRequests(ctx context.Context, repoFullName, userUUID string) ([]BitbucketPullRequest, error) {
+	filter := fmt.Sprintf(`state = "OPEN" AND reviewers.uuid = %q`, userUUID)
+	path := fmt.Sprintf("/repositories/%s/pullrequests?pagelen=50&q=%s", repoFullName, url.QueryEscape(filter))
+	return c.listPullRequests(ctx, path)
+}
+
+func (c *BitbucketClient) listPullRequests(ctx context.Context, path string) ([]BitbucketPullRequest, error) {
+	resp, err := c.doRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Values []BitbucketPullRequest `json:"values"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Values, nil
+}
+
+// BitbucketTag represents a Bitbucket tag
+type BitbucketTag struct {
+	Name    string         `json:"name"`
+	Message string         `json:"message"`
+	Date    *time.Time     `json:"date"`
+	Links   BitbucketLinks `json:"links"`
+	Tagger  *struct {
+		Raw string `json:"raw"`
+	} `json:"tagger"`
+	Target struct {
+		Hash string    `json:"hash"`
+		Date time.Time `json:"date"`
+	} `json:"target"`
+}
+
+// ListTags lists tags for a repository, most recent first
+func (c *BitbucketClient) ListTags(ctx context.Context, workspace, repo string) ([]BitbucketTag, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/refs/tags?pagelen=50&sort=-target.date", workspace, repo)
+	resp, err := c.doRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Values []BitbucketTag `json:"values"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Values, nil
+}
+
+// GetTag gets a tag by name
+func (c *BitbucketClient) GetTag(ctx context.Context, workspace, repo, name string) (*BitbucketTag, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/refs/tags/%s", workspace, repo, url.PathEscape(name))
+	resp, err := c.doRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var tag BitbucketTag
+	if err := json.NewDecoder(resp.Body).Decode(&tag); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &tag, nil
+}
+
+// CreateTag creates a tag pointing at a commit hash
+func (c *BitbucketClient) CreateTag(ctx context.Context, workspace, repo, name, hash, message string) (*BitbucketTag, error) {
+	path := fmt.Sprintf("/repositories/%s/%s/refs/tags", workspace, repo)
+	payload := map[string]interface{}{
+		"name":   name,
+		"target": map[string]string{"hash": hash},
+	}
+	if message != "" {
+		payload["message"] = message
+	}
+
+	resp, err := c.doRequest(ctx, "POST", path, payload)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var tag BitbucketTag
+	if err := json.NewDecoder(resp.Body).Decode(&tag); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &tag, nil
+}
+
+// UploadDownload uploads a local file to the repository's downloads and
+// returns its download URL
+func (c *BitbucketClient) UploadDownload(ctx context.Context, workspace, repo, filePath string) (string, error) {
+	body, contentType, err := multipartFile("files", filePath)
+	if err != nil {
+		return "", err
+	}
+
+	path := fmt.Sprintf("/repositories/%s/%s/downloads", workspace, repo)
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+path, body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	auth := base64.StdEncoding.EncodeToString([]byte(c.username + ":" + c.password))
+	req.Header.Set("Authorization", "Basic "+auth)
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("Bitbucket API error (%d): %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	return fmt.Sprintf("https://bitbucket.org/%s/%s/downloads/%s", workspace, repo, url.PathEscape(filepath.Base(filePath))), nil
+}
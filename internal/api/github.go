@@ -7,6 +7,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -71,39 +75,40 @@ func (c *GitHubClient) doRequest(ctx context.Context, method, path string, body
 	return resp, nil
 }
 
-// PullRequest represents a GitHub pull request
-type PullRequest struct {
-	ID          int       `json:"id"`
-	Number      int       `json:"number"`
-	Title       string    `json:"title"`
-	Body        string    `json:"body"`
-	State       string    `json:"state"` // open, closed
-	URL         string    `json:"html_url"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	User        User      `json:"user"`
-	Head        Branch    `json:"head"`
-	Base        Branch    `json:"base"`
-	Mergeable   *bool     `json:"mergeable"`
-	MergeableState string `json:"mergeable_state"`
-}
-
-// User represents a GitHub user
-type User struct {
+// GitHubPullRequest represents a GitHub pull request
+type GitHubPullRequest struct {
+	ID             int          `json:"id"`
+	Number         int          `json:"number"`
+	Title          string       `json:"title"`
+	Body           string       `json:"body"`
+	State          string       `json:"state"` // open, closed
+	URL            string       `json:"html_url"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+	User           GitHubUser   `json:"user"`
+	Head           GitHubBranch `json:"head"`
+	Base           GitHubBranch `json:"base"`
+	Mergeable      *bool        `json:"mergeable"`
+	MergeableState string       `json:"mergeable_state"`
+	MergedAt       *time.Time   `json:"merged_at"`
+}
+
+// GitHubUser represents a GitHub user
+type GitHubUser struct {
 	Login string `json:"login"`
 	Name  string `json:"name"`
 	Email string `json:"email"`
 }
 
-// Branch represents a GitHub branch
-type Branch struct {
-	Ref  string `json:"ref"`
-	SHA  string `json:"sha"`
-	Repo Repo   `json:"repo"`
+// GitHubBranch represents a GitHub branch
+type GitHubBranch struct {
+	Ref  string     `json:"ref"`
+	SHA  string     `json:"sha"`
+	Repo GitHubRepo `json:"repo"`
 }
 
-// Repo represents a GitHub repository
-type Repo struct {
+// GitHubRepo represents a GitHub repository
+type GitHubRepo struct {
 	ID          int    `json:"id"`
 	Name        string `json:"name"`
 	FullName    string `json:"full_name"`
@@ -112,31 +117,34 @@ type Repo struct {
 	Private     bool   `json:"private"`
 }
 
-// Issue represents a GitHub issue
-type Issue struct {
-	ID          int       `json:"id"`
-	Number      int       `json:"number"`
-	Title       string    `json:"title"`
-	Body        string    `json:"body"`
-	State       string    `json:"state"`
-	URL         string    `json:"html_url"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	User        User      `json:"user"`
-	Labels      []Label   `json:"labels"`
-	PullRequest *struct {
-		URL string `json:"url"`
+// GitHubIssue represents a GitHub issue
+type GitHubIssue struct {
+	ID        int           `json:"id"`
+	Number    int           `json:"number"`
+	Title     string        `json:"title"`
+	Body      string        `json:"body"`
+	State     string        `json:"state"`
+	URL       string        `json:"html_url"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+	User      GitHubUser    `json:"user"`
+	Labels    []GitHubLabel `json:"labels"`
+	// RepositoryURL is only populated by the search API
+	RepositoryURL string `json:"repository_url,omitempty"`
+	PullRequest   *struct {
+		URL      string     `json:"url"`
+		MergedAt *time.Time `json:"merged_at"`
 	} `json:"pull_request,omitempty"`
 }
 
-// Label represents a GitHub label
-type Label struct {
+// GitHubLabel represents a GitHub label
+type GitHubLabel struct {
 	Name  string `json:"name"`
 	Color string `json:"color"`
 }
 
 // ListPullRequests lists pull requests for a repository
-func (c *GitHubClient) ListPullRequests(ctx context.Context, owner, repo string, state string) ([]PullRequest, error) {
+func (c *GitHubClient) ListPullRequests(ctx context.Context, owner, repo string, state string) ([]GitHubPullRequest, error) {
 	if state == "" {
 		state = "open"
 	}
@@ -148,7 +156,7 @@ func (c *GitHubClient) ListPullRequests(ctx context.Context, owner, repo string,
 	}
 	defer resp.Body.Close()
 
-	var prs []PullRequest
+	var prs []GitHubPullRequest
 	if err := json.NewDecoder(resp.Body).Decode(&prs); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
@@ -157,7 +165,7 @@ func (c *GitHubClient) ListPullRequests(ctx context.Context, owner, repo string,
 }
 
 // GetPullRequest gets a specific pull request
-func (c *GitHubClient) GetPullRequest(ctx context.Context, owner, repo string, number int) (*PullRequest, error) {
+func (c *GitHubClient) GetPullRequest(ctx context.Context, owner, repo string, number int) (*GitHubPullRequest, error) {
 	path := fmt.Sprintf("/repos/%s/%s/pulls/%d", owner, repo, number)
 	resp, err := c.doRequest(ctx, "GET", path, nil)
 	if err != nil {
@@ -165,7 +173,7 @@ func (c *GitHubClient) GetPullRequest(ctx context.Context, owner, repo string, n
 	}
 	defer resp.Body.Close()
 
-	var pr PullRequest
+	var pr GitHubPullRequest
 	if err := json.NewDecoder(resp.Body).Decode(&pr); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
@@ -174,7 +182,7 @@ func (c *GitHubClient) GetPullRequest(ctx context.Context, owner, repo string, n
 }
 
 // ListIssues lists issues for a repository
-func (c *GitHubClient) ListIssues(ctx context.Context, owner, repo string, state string) ([]Issue, error) {
+func (c *GitHubClient) ListIssues(ctx context.Context, owner, repo string, state string) ([]GitHubIssue, error) {
 	if state == "" {
 		state = "open"
 	}
@@ -186,7 +194,7 @@ func (c *GitHubClient) ListIssues(ctx context.Context, owner, repo string, state
 	}
 	defer resp.Body.Close()
 
-	var issues []Issue
+	var issues []GitHubIssue
 	if err := json.NewDecoder(resp.Body).Decode(&issues); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
@@ -205,3 +213,265 @@ func (c *GitHubClient) CreatePullRequestComment(ctx context.Context, owner, repo
 	defer resp.Body.Close()
 	return nil
 }
+
+// SearchIssues searches issues and pull requests using GitHub search syntax
+func (c *GitHubClient) SearchIssues(ctx context.Context, query string) ([]GitHubIssue, error) {
+	path := "/search/issues?per_page=100&sort=updated&q=" + url.QueryEscape(query)
+	resp, err := c.doRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Items []GitHubIssue `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return result.Items, nil
+}
+
+// ListMergedPullRequests lists the pull requests merged into a repository
+// after since, following pages of search results up to the 1000 the search
+// API returns for a query. A zero since lists every merged pull request.
+func (c *GitHubClient) ListMergedPullRequests(ctx context.Context, owner, repo string, since time.Time) ([]GitHubIssue, error) {
+	query := fmt.Sprintf("repo:%s/%s is:pr is:merged", owner, repo)
+	if !since.IsZero() {
+		query += " merged:>" + since.UTC().Format(time.RFC3339)
+	}
+
+	var items []GitHubIssue
+	for page := 1; ; page++ {
+		path := fmt.Sprintf("/search/issues?per_page=%d&page=%d&q=%s", mergedPageSize, page, url.QueryEscape(query))
+		resp, err := c.doRequest(ctx, "GET", path, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var result struct {
+			TotalCount int           `json:"total_count"`
+			Items      []GitHubIssue `json:"items"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+
+		items = append(items, result.Items...)
+		if len(result.Items) < mergedPageSize || len(items) >= result.TotalCount || len(items) >= maxMergedPullRequests {
+			return items, nil
+		}
+	}
+}
+
+// GitHubNotification represents a GitHub notification thread
+type GitHubNotification struct {
+	ID        string    `json:"id"`
+	Unread    bool      `json:"unread"`
+	Reason    string    `json:"reason"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Subject   struct {
+		Title string `json:"title"`
+		URL   string `json:"url"`
+		Type  string `json:"type"`
+	} `json:"subject"`
+	Repository GitHubRepo `json:"repository"`
+}
+
+// ListNotifications lists notifications for the authenticated user
+func (c *GitHubClient) ListNotifications(ctx context.Context, all bool) ([]GitHubNotification, error) {
+	path := fmt.Sprintf("/notifications?per_page=50&all=%t", all)
+	resp, err := c.doRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var notifications []GitHubNotification
+	if err := json.NewDecoder(resp.Body).Decode(&notifications); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return notifications, nil
+}
+
+// MarkThreadRead marks a notification thread as read
+func (c *GitHubClient) MarkThreadRead(ctx context.Context, threadID string) error {
+	resp, err := c.doRequest(ctx, "PATCH", "/notifications/threads/"+threadID, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
+// MarkThreadDone marks a notification thread as done, removing it from the inbox
+func (c *GitHubClient) MarkThreadDone(ctx context.Context, threadID string) error {
+	resp, err := c.doRequest(ctx, "DELETE", "/notifications/threads/"+threadID, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
+// GitHubRelease represents a GitHub release
+type GitHubRelease struct {
+	ID              int                  `json:"id"`
+	TagName         string               `json:"tag_name"`
+	TargetCommitish string               `json:"target_commitish"`
+	Name            string               `json:"name"`
+	Body            string               `json:"body"`
+	URL             string               `json:"html_url"`
+	UploadURL       string               `json:"upload_url"`
+	Draft           bool                 `json:"draft"`
+	Prerelease      bool                 `json:"prerelease"`
+	CreatedAt       time.Time            `json:"created_at"`
+	PublishedAt     *time.Time           `json:"published_at"`
+	Author          GitHubUser           `json:"author"`
+	Assets          []GitHubReleaseAsset `json:"assets"`
+}
+
+// GitHubReleaseAsset represents a file attached to a GitHub release
+type GitHubReleaseAsset struct {
+	Name        string `json:"name"`
+	Size        int64  `json:"size"`
+	DownloadURL string `json:"browser_download_url"`
+}
+
+// ListReleases lists releases for a repository
+func (c *GitHubClient) ListReleases(ctx context.Context, owner, repo string) ([]GitHubRelease, error) {
+	path := fmt.Sprintf("/repos/%s/%s/releases?per_page=50", owner, repo)
+	resp, err := c.doRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var releases []GitHubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return releases, nil
+}
+
+// GetReleaseByTag gets the release for a tag
+func (c *GitHubClient) GetReleaseByTag(ctx context.Context, owner, repo, tag string) (*GitHubRelease, error) {
+	path := fmt.Sprintf("/repos/%s/%s/releases/tags/%s", owner, repo, url.PathEscape(tag))
+	resp, err := c.doRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var release GitHubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &release, nil
+}
+
+// CreateRelease creates a release
+func (c *GitHubClient) CreateRelease(ctx context.Context, owner, repo string, opts ReleaseOptions) (*GitHubRelease, error) {
+	path := fmt.Sprintf("/repos/%s/%s/releases", owner, repo)
+	payload := map[string]interface{}{
+		"tag_name":   opts.TagName,
+		"name":       opts.Name,
+		"body":       opts.Body,
+		"draft":      opts.Draft,
+		"prerelease": opts.Prerelease,
+	}
+	if opts.Target != "" {
+		payload["target_commitish"] = opts.Target
+	}
+
+	resp, err := c.doRequest(ctx, "POST", path, payload)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var release GitHubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &release, nil
+}
+
+// UploadReleaseAsset uploads a local file to a release's upload URL
+func (c *GitHubClient) UploadReleaseAsset(ctx context.Context, uploadURL, filePath string) (*GitHubReleaseAsset, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open asset: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read asset: %w", err)
+	}
+
+	// upload_url is a URI template such as .../assets{?name,label}
+	if i := strings.Index(uploadURL, "{"); i >= 0 {
+		uploadURL = uploadURL[:i]
+	}
+	uploadURL += "?name=" + url.QueryEscape(filepath.Base(filePath))
+
+	req, err := http.NewRequestWithContext(ctx, "POST", uploadURL, f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.ContentLength = info.Size()
+	req.Header.Set("Authorization", "token "+c.token)
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("GitHub API error (%d): %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var asset GitHubReleaseAsset
+	if err := json.NewDecoder(resp.Body).Decode(&asset); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &asset, nil
+}
+
+// GetAuthenticatedUser returns the user the token belongs to, along with
+// the token's scopes (classic tokens only) and expiry when GitHub reports them
+func (c *GitHubClient) GetAuthenticatedUser(ctx context.Context) (*GitHubUser, *TokenInfo, error) {
+	resp, err := c.doRequest(ctx, "GET", "/user", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	var user GitHubUser
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	info := &TokenInfo{Scopes: scopesFromHeader(resp.Header, "X-OAuth-Scopes")}
+	if expiry := resp.Header.Get("GitHub-Authentication-Token-Expiration"); expiry != "" {
+		if t, err := time.Parse("2006-01-02 15:04:05 MST", expiry); err == nil {
+			info.ExpiresAt = t.UTC().Format(time.RFC3339)
+		} else {
+			info.ExpiresAt = expiry
+		}
+	}
+	return &user, info, nil
+}
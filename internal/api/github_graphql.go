@@ -0,0 +1,336 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// githubGraphQLPageSize is the most nodes GitHub returns per connection
+	githubGraphQLPageSize = 100
+	// githubGraphQLReposPerQuery bounds how many aliased repositories go into
+	// a single query, keeping each request well under GitHub's node limits
+	githubGraphQLReposPerQuery = 20
+)
+
+// GitHubPageInfo represents GraphQL connection pagination info
+type GitHubPageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+// GitHubGraphQLPullRequest represents a pull request returned by the GraphQL API
+type GitHubGraphQLPullRequest struct {
+	DatabaseID     int       `json:"databaseId"`
+	Number         int       `json:"number"`
+	Title          string    `json:"title"`
+	Body           string    `json:"body"`
+	State          string    `json:"state"` // OPEN, CLOSED, MERGED
+	URL            string    `json:"url"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+	HeadRefName    string    `json:"headRefName"`
+	BaseRefName    string    `json:"baseRefName"`
+	ReviewDecision string    `json:"reviewDecision"` // APPROVED, CHANGES_REQUESTED, REVIEW_REQUIRED
+	Author         *struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	Commits struct {
+		Nodes []struct {
+			Commit struct {
+				StatusCheckRollup *struct {
+					State string `json:"state"` // SUCCESS, FAILURE, PENDING, ERROR, EXPECTED
+				} `json:"statusCheckRollup"`
+			} `json:"commit"`
+		} `json:"nodes"`
+	} `json:"commits"`
+}
+
+// GitHubGraphQLIssue represents an issue returned by the GraphQL API
+type GitHubGraphQLIssue struct {
+	DatabaseID int       `json:"databaseId"`
+	Number     int       `json:"number"`
+	Title      string    `json:"title"`
+	Body       string    `json:"body"`
+	State      string    `json:"state"` // OPEN, CLOSED
+	URL        string    `json:"url"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+	Author     *struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+}
+
+// GitHubRepoBatch holds everything fetched for one repository in a batch
+type GitHubRepoBatch struct {
+	PullRequests []GitHubGraphQLPullRequest
+	Issues       []GitHubGraphQLIssue
+	Err          error
+}
+
+type githubGraphQLError struct {
+	Message string        `json:"message"`
+	Type    string        `json:"type"`
+	Path    []interface{} `json:"path"`
+}
+
+type githubRepoNode struct {
+	PullRequests *struct {
+		PageInfo GitHubPageInfo             `json:"pageInfo"`
+		Nodes    []GitHubGraphQLPullRequest `json:"nodes"`
+	} `json:"pullRequests"`
+	Issues *struct {
+		PageInfo GitHubPageInfo       `json:"pageInfo"`
+		Nodes    []GitHubGraphQLIssue `json:"nodes"`
+	} `json:"issues"`
+}
+
+const githubPullRequestFragment = `fragment prs on PullRequestConnection {
+  pageInfo { hasNextPage endCursor }
+  nodes {
+    databaseId number title body state url createdAt updatedAt
+    headRefName baseRefName reviewDecision
+    author { login }
+    commits(last: 1) { nodes { commit { statusCheckRollup { state } } } }
+  }
+}
+`
+
+const githubIssueFragment = `fragment issues on IssueConnection {
+  pageInfo { hasNextPage endCursor }
+  nodes {
+    databaseId number title body state url createdAt updatedAt
+    author { login }
+    labels(first: 20) { nodes { name } }
+  }
+}
+`
+
+// graphQL performs a GraphQL query against the GitHub API. Partial results
+// are decoded into data even when the response also carries errors.
+func (c *GitHubClient) graphQL(ctx context.Context, query string, variables map[string]interface{}, data interface{}) ([]githubGraphQLError, error) {
+	payload := map[string]interface{}{
+		"query":     query,
+		"variables": variables,
+	}
+
+	resp, err := c.doRequest(ctx, "POST", "/graphql", payload)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Data   json.RawMessage      `json:"data"`
+		Errors []githubGraphQLError `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(result.Data) == 0 || string(result.Data) == "null" {
+		if len(result.Errors) > 0 {
+			return nil, fmt.Errorf("GitHub GraphQL error: %s", result.Errors[0].Message)
+		}
+		return nil, fmt.Errorf("GitHub GraphQL error: empty response")
+	}
+
+	if data != nil {
+		if err := json.Unmarshal(result.Data, data); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+
+	return result.Errors, nil
+}
+
+// githubBatchCursor tracks pagination state for one repository
+type githubBatchCursor struct {
+	prCursor    string
+	prDone      bool
+	prLeft      int
+	issueCursor string
+	issueDone   bool
+	issueLeft   int
+}
+
+// BatchRepositories fetches pull requests and/or issues for many repositories
+// using aliased GraphQL queries, following cursors until every connection is
+// exhausted or opts.Limit items have been fetched for it. Errors scoped to a single repository are reported in its
+// GitHubRepoBatch rather than failing the whole call.
+func (c *GitHubClient) BatchRepositories(ctx context.Context, repos []RepoRef, opts BatchOptions) (map[RepoRef]*GitHubRepoBatch, error) {
+	results := make(map[RepoRef]*GitHubRepoBatch, len(repos))
+	for start := 0; start < len(repos); start += githubGraphQLReposPerQuery {
+		end := start + githubGraphQLReposPerQuery
+		if end > len(repos) {
+			end = len(repos)
+		}
+		if err := c.batchChunk(ctx, repos[start:end], opts, results); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+func (c *GitHubClient) batchChunk(ctx context.Context, repos []RepoRef, opts BatchOptions, results map[RepoRef]*GitHubRepoBatch) error {
+	cursors := make([]*githubBatchCursor, len(repos))
+	for i, repo := range repos {
+		results[repo] = &GitHubRepoBatch{}
+		cursors[i] = &githubBatchCursor{
+			prDone:    !opts.PullRequests,
+			prLeft:    opts.limit(),
+			issueDone: !opts.Issues,
+			issueLeft: opts.limit(),
+		}
+	}
+
+	for {
+		query, variables, aliases := buildGitHubBatchQuery(repos, cursors, opts.State)
+		if len(aliases) == 0 {
+			return nil
+		}
+
+		var data map[string]json.RawMessage
+		gqlErrors, err := c.graphQL(ctx, query, variables, &data)
+		if err != nil {
+			return err
+		}
+
+		for _, gqlErr := range gqlErrors {
+			if len(gqlErr.Path) == 0 {
+				continue
+			}
+			alias, _ := gqlErr.Path[0].(string)
+			if i, ok := aliases[alias]; ok {
+				results[repos[i]].Err = fmt.Errorf("GitHub GraphQL error: %s", gqlErr.Message)
+				cursors[i].prDone = true
+				cursors[i].issueDone = true
+			}
+		}
+
+		for alias, i := range aliases {
+			if results[repos[i]].Err != nil {
+				continue
+			}
+
+			raw, ok := data[alias]
+			if !ok || string(raw) == "null" {
+				results[repos[i]].Err = fmt.Errorf("repository %s not found", repos[i])
+				cursors[i].prDone = true
+				cursors[i].issueDone = true
+				continue
+			}
+
+			var node githubRepoNode
+			if err := json.Unmarshal(raw, &node); err != nil {
+				return fmt.Errorf("failed to decode response: %w", err)
+			}
+
+			batch := results[repos[i]]
+			cursor := cursors[i]
+			if node.PullRequests != nil {
+				batch.PullRequests = append(batch.PullRequests, node.PullRequests.Nodes...)
+				cursor.prCursor = node.PullRequests.PageInfo.EndCursor
+				cursor.prLeft -= len(node.PullRequests.Nodes)
+				cursor.prDone = !node.PullRequests.PageInfo.HasNextPage || cursor.prLeft <= 0
+			}
+			if node.Issues != nil {
+				batch.Issues = append(batch.Issues, node.Issues.Nodes...)
+				cursor.issueCursor = node.Issues.PageInfo.EndCursor
+				cursor.issueLeft -= len(node.Issues.Nodes)
+				cursor.issueDone = !node.Issues.PageInfo.HasNextPage || cursor.issueLeft <= 0
+			}
+		}
+	}
+}
+
+// buildGitHubBatchQuery builds one aliased query covering every repository
+// that still has pages left. It returns the alias → repo index mapping.
+func buildGitHubBatchQuery(repos []RepoRef, cursors []*githubBatchCursor, state string) (string, map[string]interface{}, map[string]int) {
+	prStates, issueStates := githubGraphQLStates(state)
+
+	var params, body strings.Builder
+	variables := make(map[string]interface{})
+	aliases := make(map[string]int)
+	usesPRs, usesIssues := false, false
+
+	for i, repo := range repos {
+		cursor := cursors[i]
+		if cursor.prDone && cursor.issueDone {
+			continue
+		}
+
+		alias := fmt.Sprintf("r%d", i)
+		aliases[alias] = i
+		fmt.Fprintf(&params, "$o%d: String!, $n%d: String!, ", i, i)
+		variables[fmt.Sprintf("o%d", i)] = repo.Owner
+		variables[fmt.Sprintf("n%d", i)] = repo.Name
+
+		fmt.Fprintf(&body, "  %s: repository(owner: $o%d, name: $n%d) {\n", alias, i, i)
+		if !cursor.prDone {
+			usesPRs = true
+			fmt.Fprintf(&params, "$pc%d: String, ", i)
+			variables[fmt.Sprintf("pc%d", i)] = nullableCursor(cursor.prCursor)
+			fmt.Fprintf(&body, "    pullRequests(first: %d, after: $pc%d%s, orderBy: {field: UPDATED_AT, direction: DESC}) { ...prs }\n",
+				pageSize(cursor.prLeft), i, prStates)
+		}
+		if !cursor.issueDone {
+			usesIssues = true
+			fmt.Fprintf(&params, "$ic%d: String, ", i)
+			variables[fmt.Sprintf("ic%d", i)] = nullableCursor(cursor.issueCursor)
+			fmt.Fprintf(&body, "    issues(first: %d, after: $ic%d%s, orderBy: {field: UPDATED_AT, direction: DESC}) { ...issues }\n",
+				pageSize(cursor.issueLeft), i, issueStates)
+		}
+		body.WriteString("  }\n")
+	}
+
+	if len(aliases) == 0 {
+		return "", nil, aliases
+	}
+
+	var query strings.Builder
+	fmt.Fprintf(&query, "query(%s) {\n%s}\n", strings.TrimSuffix(params.String(), ", "), body.String())
+	if usesPRs {
+		query.WriteString(githubPullRequestFragment)
+	}
+	if usesIssues {
+		query.WriteString(githubIssueFragment)
+	}
+
+	return query.String(), variables, aliases
+}
+
+// githubGraphQLStates maps a REST-style state filter to GraphQL state arguments
+func githubGraphQLStates(state string) (prStates, issueStates string) {
+	switch state {
+	case "closed":
+		return ", states: [CLOSED, MERGED]", ", states: [CLOSED]"
+	case "all":
+		return "", ""
+	default:
+		return ", states: [OPEN]", ", states: [OPEN]"
+	}
+}
+
+// pageSize returns how many nodes to request when left are still wanted
+func pageSize(left int) int {
+	if left < githubGraphQLPageSize {
+		return left
+	}
+	return githubGraphQLPageSize
+}
+
+func nullableCursor(cursor string) interface{} {
+	if cursor == "" {
+		return nil
+	}
+	return cursor
+}
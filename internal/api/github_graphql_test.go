@@ -0,0 +1,153 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBatchRepositoriesPaginatesAndReportsMissingRepos(t *testing.T) {
+	requests := 0
+	// The handler runs on the server's goroutine, so failures are recorded
+	// and checked once the fetch returns
+	var handlerErr error
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/graphql" {
+			handlerErr = fmt.Errorf("unexpected path %s", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		requests++
+
+		var payload struct {
+			Query     string                 `json:"query"`
+			Variables map[string]interface{} `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			handlerErr = fmt.Errorf("failed to decode request: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		switch requests {
+		case 1:
+			if !strings.Contains(payload.Query, "r0: repository") || !strings.Contains(payload.Query, "r1: repository") {
+				t.Errorf("expected both repos in first query, got:\n%s", payload.Query)
+			}
+			w.Write([]byte(`{
+				"data": {
+					"r0": {"pullRequests": {
+						"pageInfo": {"hasNextPage": true, "endCursor": "c1"},
+						"nodes": [{"number": 1, "state": "OPEN", "reviewDecision": "APPROVED",
+							"commits": {"nodes": [{"commit": {"statusCheckRollup": {"state": "SUCCESS"}}}]}}]
+					}},
+					"r1": null
+				},
+				"errors": [{"message": "Could not resolve to a Repository", "type": "NOT_FOUND", "path": ["r1"]}]
+			}`))
+		case 2:
+			if strings.Contains(payload.Query, "r1: repository") {
+				t.Errorf("finished repo should not be queried again")
+			}
+			if payload.Variables["pc0"] != "c1" {
+				t.Errorf("expected cursor c1, got %v", payload.Variables["pc0"])
+			}
+			w.Write([]byte(`{"data": {"r0": {"pullRequests": {
+				"pageInfo": {"hasNextPage": false, "endCursor": "c2"},
+				"nodes": [{"number": 2, "state": "MERGED"}]
+			}}}}`))
+		default:
+			handlerErr = fmt.Errorf("unexpected request %d", requests)
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	client := NewGitHubClient("token")
+	client.baseURL = server.URL
+	adapter := &GitHubProviderAdapter{client: client}
+
+	found := RepoRef{Owner: "acme", Name: "api"}
+	missing := RepoRef{Owner: "acme", Name: "gone"}
+	results := FetchRepoItems(adapter, []RepoRef{found, missing}, BatchOptions{State: "open", PullRequests: true})
+
+	if handlerErr != nil {
+		t.Fatal(handlerErr)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+
+	items := results[found]
+	if items.Err != nil {
+		t.Fatalf("Unexpected error: %v", items.Err)
+	}
+	if len(items.PullRequests) != 2 {
+		t.Fatalf("Expected 2 pull requests, got %d", len(items.PullRequests))
+	}
+	if items.PullRequests[0].ReviewState != "approved" || items.PullRequests[0].CheckStatus != "success" {
+		t.Errorf("Unexpected review/check state: %+v", items.PullRequests[0])
+	}
+	if items.PullRequests[1].State != "closed" {
+		t.Errorf("Expected merged PR to be reported as closed, got %s", items.PullRequests[1].State)
+	}
+
+	if results[missing].Err == nil {
+		t.Errorf("Expected error for missing repository")
+	}
+}
+
+func TestBatchRepositoriesStopsAtLimit(t *testing.T) {
+	requests := 0
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var payload struct {
+			Query string `json:"query"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		query = payload.Query
+		w.Write([]byte(`{"data": {"r0": {"pullRequests": {
+			"pageInfo": {"hasNextPage": true, "endCursor": "c1"},
+			"nodes": [{"number": 1}, {"number": 2}]
+		}}}}`))
+	}))
+	defer server.Close()
+
+	client := NewGitHubClient("token")
+	client.baseURL = server.URL
+	repo := RepoRef{Owner: "acme", Name: "api"}
+	results, err := client.BatchRepositories(context.Background(), []RepoRef{repo}, BatchOptions{State: "all", PullRequests: true, Limit: 2})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if requests != 1 {
+		t.Errorf("Expected paging to stop at the limit after 1 request, got %d", requests)
+	}
+	if !strings.Contains(query, "pullRequests(first: 2,") {
+		t.Errorf("Expected the page size to be capped at the limit, got:\n%s", query)
+	}
+	if len(results[repo].PullRequests) != 2 {
+		t.Errorf("Expected 2 pull requests, got %d", len(results[repo].PullRequests))
+	}
+}
+
+func TestFetchRepoItemsLimitsRESTFallback(t *testing.T) {
+	p := &fakeProvider{name: "gitlab", prs: []PullRequest{
+		{Number: 1, UpdatedAt: "2024-01-01T00:00:00Z"},
+		{Number: 2, UpdatedAt: "2024-03-01T00:00:00Z"},
+		{Number: 3, UpdatedAt: "2024-02-01T00:00:00Z"},
+	}}
+	repo := RepoRef{Owner: "acme", Name: "api"}
+	results := FetchRepoItems(p, []RepoRef{repo}, BatchOptions{State: "all", PullRequests: true, Limit: 2})
+
+	prs := results[repo].PullRequests
+	if len(prs) != 2 || prs[0].Number != 2 || prs[1].Number != 3 {
+		t.Errorf("Expected the 2 most recently updated pull requests, got %+v", prs)
+	}
+}
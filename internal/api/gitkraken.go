@@ -193,3 +193,20 @@ func (c *Client) ListCloudPatches(ctx context.Context) ([]CloudPatch, error) {
 func (c *Client) DeleteCloudPatch(ctx context.Context, patchID string) error {
 	return c.Delete(ctx, "/patches/"+patchID)
 }
+
+// User is the GitKraken account the CLI is authenticated as
+type User struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+}
+
+// GetCurrentUser returns the authenticated GitKraken account
+func (c *Client) GetCurrentUser(ctx context.Context) (*User, error) {
+	var result User
+	if err := c.Get(ctx, "/user", &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -7,6 +7,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -72,17 +76,18 @@ func (c *GitLabClient) doRequest(ctx context.Context, method, path string, body
 
 // GitLabMergeRequest represents a GitLab merge request
 type GitLabMergeRequest struct {
-	ID          int       `json:"id"`
-	IID         int       `json:"iid"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	State       string    `json:"state"`
-	URL         string    `json:"web_url"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	Author      GitLabUser `json:"author"`
-	SourceBranch string   `json:"source_branch"`
-	TargetBranch string   `json:"target_branch"`
+	ID           int        `json:"id"`
+	IID          int        `json:"iid"`
+	Title        string     `json:"title"`
+	Description  string     `json:"description"`
+	State        string     `json:"state"`
+	URL          string     `json:"web_url"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	Author       GitLabUser `json:"author"`
+	SourceBranch string     `json:"source_branch"`
+	TargetBranch string     `json:"target_branch"`
+	MergedAt     *time.Time `json:"merged_at"`
 }
 
 // GitLabUser represents a GitLab user
@@ -95,14 +100,14 @@ type GitLabUser struct {
 
 // GitLabIssue represents a GitLab issue
 type GitLabIssue struct {
-	ID          int       `json:"id"`
-	IID         int       `json:"iid"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	State       string    `json:"state"`
-	URL         string    `json:"web_url"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          int        `json:"id"`
+	IID         int        `json:"iid"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	State       string     `json:"state"`
+	URL         string     `json:"web_url"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
 	Author      GitLabUser `json:"author"`
 	Labels      []string   `json:"labels"`
 }
@@ -165,3 +170,280 @@ func (c *GitLabClient) ListIssues(ctx context.Context, projectID string, state s
 
 	return issues, nil
 }
+
+// SearchMergeRequests searches merge requests matching q, merging the results
+// of every parameter set needed to express it
+func (c *GitLabClient) SearchMergeRequests(ctx context.Context, q SearchQuery) ([]GitLabMergeRequest, error) {
+	path := gitlabSearchPath(q, "merge_requests")
+	seen := make(map[int]bool)
+	var mrs []GitLabMergeRequest
+	for _, params := range gitlabSearchParams(q, "merge_requests") {
+		var page []GitLabMergeRequest
+		if err := c.getJSON(ctx, path+"?"+params.Encode(), &page); err != nil {
+			return nil, err
+		}
+		for _, mr := range page {
+			if !seen[mr.ID] {
+				seen[mr.ID] = true
+				mrs = append(mrs, mr)
+			}
+		}
+	}
+	return mrs, nil
+}
+
+// SearchIssues searches issues matching q, merging the results of every
+// parameter set needed to express it
+func (c *GitLabClient) SearchIssues(ctx context.Context, q SearchQuery) ([]GitLabIssue, error) {
+	path := gitlabSearchPath(q, "issues")
+	seen := make(map[int]bool)
+	var issues []GitLabIssue
+	for _, params := range gitlabSearchParams(q, "issues") {
+		var page []GitLabIssue
+		if err := c.getJSON(ctx, path+"?"+params.Encode(), &page); err != nil {
+			return nil, err
+		}
+		for _, issue := range page {
+			if !seen[issue.ID] {
+				seen[issue.ID] = true
+				issues = append(issues, issue)
+			}
+		}
+	}
+	return issues, nil
+}
+
+// ListMergedMergeRequests lists the merge requests of a project merged after
+// since, following pages until they run out. A zero since lists every merged
+// merge request.
+func (c *GitLabClient) ListMergedMergeRequests(ctx context.Context, projectID string, since time.Time) ([]GitLabMergeRequest, error) {
+	params := url.Values{}
+	params.Set("state", "merged")
+	params.Set("order_by", "updated_at")
+	params.Set("per_page", strconv.Itoa(mergedPageSize))
+	// A merge request is last updated no earlier than it was merged
+	if !since.IsZero() {
+		params.Set("updated_after", since.UTC().Format(time.RFC3339))
+	}
+
+	var mrs []GitLabMergeRequest
+	for page := 1; ; page++ {
+		params.Set("page", strconv.Itoa(page))
+		var batch []GitLabMergeRequest
+		path := fmt.Sprintf("/projects/%s/merge_requests?%s", url.PathEscape(projectID), params.Encode())
+		if err := c.getJSON(ctx, path, &batch); err != nil {
+			return nil, err
+		}
+		mrs = append(mrs, batch...)
+		if len(batch) < mergedPageSize || len(mrs) >= maxMergedPullRequests {
+			return mrs, nil
+		}
+	}
+}
+
+func (c *GitLabClient) getJSON(ctx context.Context, path string, result interface{}) error {
+	resp, err := c.doRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// GitLabTodo represents a GitLab to-do item
+type GitLabTodo struct {
+	ID         int       `json:"id"`
+	ActionName string    `json:"action_name"`
+	TargetType string    `json:"target_type"`
+	TargetURL  string    `json:"target_url"`
+	State      string    `json:"state"` // pending, done
+	CreatedAt  time.Time `json:"created_at"`
+	Project    struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	Target struct {
+		Title string `json:"title"`
+	} `json:"target"`
+}
+
+// ListTodos lists the authenticated user's to-do items. Pending items are
+// always returned; done items only when all is set.
+func (c *GitLabClient) ListTodos(ctx context.Context, all bool) ([]GitLabTodo, error) {
+	states := []string{"pending"}
+	if all {
+		states = append(states, "done")
+	}
+
+	var todos []GitLabTodo
+	for _, state := range states {
+		var page []GitLabTodo
+		if err := c.getJSON(ctx, "/todos?per_page=50&state="+state, &page); err != nil {
+			return nil, err
+		}
+		todos = append(todos, page...)
+	}
+	return todos, nil
+}
+
+// MarkTodoDone marks a to-do item as done
+func (c *GitLabClient) MarkTodoDone(ctx context.Context, id string) error {
+	resp, err := c.doRequest(ctx, "POST", fmt.Sprintf("/todos/%s/mark_as_done", id), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return nil
+}
+
+// GitLabRelease represents a GitLab release
+type GitLabRelease struct {
+	TagName         string     `json:"tag_name"`
+	Name            string     `json:"name"`
+	Description     string     `json:"description"`
+	CreatedAt       time.Time  `json:"created_at"`
+	ReleasedAt      *time.Time `json:"released_at"`
+	UpcomingRelease bool       `json:"upcoming_release"`
+	Author          GitLabUser `json:"author"`
+	Commit          struct {
+		ID string `json:"id"`
+	} `json:"commit"`
+	Links struct {
+		Self string `json:"self"`
+	} `json:"_links"`
+	Assets struct {
+		Links []struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"links"`
+	} `json:"assets"`
+}
+
+// ListReleases lists releases for a project
+func (c *GitLabClient) ListReleases(ctx context.Context, projectID string) ([]GitLabRelease, error) {
+	var releases []GitLabRelease
+	path := fmt.Sprintf("/projects/%s/releases?per_page=50", url.PathEscape(projectID))
+	if err := c.getJSON(ctx, path, &releases); err != nil {
+		return nil, err
+	}
+	return releases, nil
+}
+
+// GetRelease gets the release for a tag
+func (c *GitLabClient) GetRelease(ctx context.Context, projectID, tag string) (*GitLabRelease, error) {
+	var release GitLabRelease
+	path := fmt.Sprintf("/projects/%s/releases/%s", url.PathEscape(projectID), url.PathEscape(tag))
+	if err := c.getJSON(ctx, path, &release); err != nil {
+		return nil, err
+	}
+	return &release, nil
+}
+
+// CreateRelease creates a release, creating the tag from ref if it does not exist
+func (c *GitLabClient) CreateRelease(ctx context.Context, projectID string, opts ReleaseOptions) (*GitLabRelease, error) {
+	path := fmt.Sprintf("/projects/%s/releases", url.PathEscape(projectID))
+	payload := map[string]interface{}{
+		"tag_name":    opts.TagName,
+		"name":        opts.Name,
+		"description": opts.Body,
+	}
+	if opts.Target != "" {
+		payload["ref"] = opts.Target
+	}
+
+	resp, err := c.doRequest(ctx, "POST", path, payload)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var release GitLabRelease
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &release, nil
+}
+
+// UploadReleaseAsset uploads a local file to the project and links it to the
+// release for tag. It returns the public URL of the uploaded file.
+func (c *GitLabClient) UploadReleaseAsset(ctx context.Context, projectID, tag, filePath string) (string, error) {
+	body, contentType, err := multipartFile("file", filePath)
+	if err != nil {
+		return "", err
+	}
+
+	uploadPath := fmt.Sprintf("/projects/%s/uploads", url.PathEscape(projectID))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+uploadPath, body)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("GitLab API error (%d): %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	var upload struct {
+		FullPath string `json:"full_path"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&upload); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	// Upload paths are relative to the GitLab host, not the API root
+	assetURL := strings.TrimSuffix(c.baseURL, "/api/v4") + upload.FullPath
+	linkPath := fmt.Sprintf("/projects/%s/releases/%s/assets/links", url.PathEscape(projectID), url.PathEscape(tag))
+	link := map[string]string{
+		"name": filepath.Base(filePath),
+		"url":  assetURL,
+	}
+	linkResp, err := c.doRequest(ctx, "POST", linkPath, link)
+	if err != nil {
+		return "", err
+	}
+	linkResp.Body.Close()
+
+	return assetURL, nil
+}
+
+// GetCurrentUser returns the authenticated user
+func (c *GitLabClient) GetCurrentUser(ctx context.Context) (*GitLabUser, error) {
+	var user GitLabUser
+	if err := c.getJSON(ctx, "/user", &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetTokenInfo returns the scopes and expiry of the personal access token
+// in use. OAuth tokens are not personal access tokens and return an error.
+func (c *GitLabClient) GetTokenInfo(ctx context.Context) (*TokenInfo, error) {
+	var token struct {
+		Scopes    []string `json:"scopes"`
+		ExpiresAt string   `json:"expires_at"` // date, e.g. 2025-01-31
+	}
+	if err := c.getJSON(ctx, "/personal_access_tokens/self", &token); err != nil {
+		return nil, err
+	}
+
+	info := &TokenInfo{Scopes: token.Scopes, ExpiresAt: token.ExpiresAt}
+	if info.Scopes == nil {
+		info.Scopes = []string{}
+	}
+	if t, err := time.Parse("2006-01-02", token.ExpiresAt); err == nil {
+		info.ExpiresAt = t.Format(time.RFC3339)
+	}
+	return info, nil
+}
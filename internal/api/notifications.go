@@ -0,0 +1,109 @@
+package api
+
+import (
+	"errors"
+	"strings"
+)
+
+// Notification reasons shared by all providers
+const (
+	ReasonReviewRequested = "review_requested"
+	ReasonMentioned       = "mentioned"
+	ReasonAssigned        = "assigned"
+	ReasonAuthor          = "author"
+	ReasonComment         = "comment"
+	ReasonOther           = "other"
+)
+
+// ErrNotSupported is returned when a provider has no server-side equivalent
+// for an operation, such as marking Bitbucket activity as read
+var ErrNotSupported = errors.New("not supported by provider")
+
+// Notification is a unified inbox entry: a GitHub notification, a GitLab
+// to-do or Bitbucket pull request activity
+type Notification struct {
+	Provider  string
+	ID        string
+	Reason    string // one of the Reason* constants
+	Type      string // "pr", "issue" or "other"
+	Title     string
+	Repo      string
+	URL       string
+	UpdatedAt string
+	Unread    bool
+}
+
+// Key returns the provider-qualified identifier used by gk inbox
+func (n Notification) Key() string {
+	return n.Provider + ":" + n.ID
+}
+
+// NotificationOptions controls which notifications are listed
+type NotificationOptions struct {
+	All bool // include read/done notifications
+	// Repos lists repositories to scan on providers without a server-side inbox
+	Repos []RepoRef
+}
+
+// NotificationSource is implemented by providers that can feed gk inbox
+type NotificationSource interface {
+	ListNotifications(opts NotificationOptions) ([]Notification, error)
+	MarkNotificationRead(id string) error
+	MarkNotificationDone(id string) error
+}
+
+// githubReason maps a GitHub notification reason to a unified reason
+func githubReason(reason string) string {
+	switch reason {
+	case "review_requested":
+		return ReasonReviewRequested
+	case "mention", "team_mention":
+		return ReasonMentioned
+	case "assign":
+		return ReasonAssigned
+	case "author":
+		return ReasonAuthor
+	case "comment":
+		return ReasonComment
+	default:
+		return ReasonOther
+	}
+}
+
+// gitlabReason maps a GitLab to-do action to a unified reason
+func gitlabReason(action string) string {
+	switch action {
+	case "review_requested", "approval_required":
+		return ReasonReviewRequested
+	case "mentioned", "directly_addressed":
+		return ReasonMentioned
+	case "assigned":
+		return ReasonAssigned
+	default:
+		return ReasonOther
+	}
+}
+
+// notificationType maps provider subject types to "pr", "issue" or "other"
+func notificationType(subject string) string {
+	switch subject {
+	case "PullRequest", "MergeRequest":
+		return "pr"
+	case "Issue":
+		return "issue"
+	default:
+		return "other"
+	}
+}
+
+// githubHTMLURL converts a GitHub API subject URL into its web URL, e.g.
+// https://api.github.com/repos/o/r/pulls/1 → https://github.com/o/r/pull/1
+func githubHTMLURL(apiURL string) string {
+	i := strings.Index(apiURL, "/repos/")
+	if i < 0 {
+		return apiURL
+	}
+	path := apiURL[i+len("/repos/"):]
+	path = strings.Replace(path, "/pulls/", "/pull/", 1)
+	return "https://github.com/" + path
+}
@@ -15,41 +15,52 @@ type Provider interface {
 
 // PullRequest is a unified pull request structure
 type PullRequest struct {
-	Provider    string
-	ID          string
-	Number      int
-	Title       string
-	Body        string
-	State       string
-	URL         string
-	Author      string
+	Provider     string
+	Repo         string // owner/name, set when results span repositories
+	ID           string
+	Number       int
+	Title        string
+	Body         string
+	State        string
+	URL          string
+	Author       string
 	SourceBranch string
 	TargetBranch string
-	CreatedAt   string
-	UpdatedAt   string
+	ReviewState  string // approved, changes_requested, review_required (when known)
+	CheckStatus  string // success, failure, pending, error (when known)
+	CreatedAt    string
+	UpdatedAt    string
+	MergedAt     string
 }
 
 // Issue is a unified issue structure
 type Issue struct {
-	Provider string
-	ID       string
-	Number   int
-	Title    string
-	Body     string
-	State    string
-	URL      string
-	Author   string
-	Labels   []string
+	Provider  string
+	Repo      string // owner/name, set when results span repositories
+	ID        string
+	Number    int
+	Title     string
+	Body      string
+	State     string
+	URL       string
+	Author    string
+	Labels    []string
 	CreatedAt string
 	UpdatedAt string
 }
 
+// TokenFunc returns a current access token, refreshing it if needed
+type TokenFunc func() (string, error)
+
 // ProviderFactory creates provider clients
 type ProviderFactory struct {
 	githubToken   string
 	gitlabToken   string
 	bitbucketUser string
 	bitbucketPass string
+	tokenFuncs    map[string]TokenFunc
+	accounts      map[string]*ProviderFactory
+	routes        []Route
 }
 
 // NewProviderFactory creates a new provider factory
@@ -67,25 +78,71 @@ func (f *ProviderFactory) SetGitLabToken(token string) {
 	f.gitlabToken = token
 }
 
+// SetTokenFunc sets a function that supplies the token for a provider each
+// time a client is created, for tokens that expire and need refreshing
+func (f *ProviderFactory) SetTokenFunc(provider string, fn TokenFunc) {
+	if f.tokenFuncs == nil {
+		f.tokenFuncs = make(map[string]TokenFunc)
+	}
+	f.tokenFuncs[provider] = fn
+}
+
+// token returns the current token for a token-based provider
+func (f *ProviderFactory) token(provider string) (string, error) {
+	if fn, ok := f.tokenFuncs[provider]; ok {
+		return fn()
+	}
+	switch provider {
+	case "github":
+		return f.githubToken, nil
+	case "gitlab":
+		return f.gitlabToken, nil
+	}
+	return "", nil
+}
+
 // SetBitbucketCreds sets Bitbucket credentials
 func (f *ProviderFactory) SetBitbucketCreds(username, password string) {
 	f.bitbucketUser = username
 	f.bitbucketPass = password
 }
 
+// ConfiguredProviders returns the names of providers that have credentials set
+func (f *ProviderFactory) ConfiguredProviders() []string {
+	var names []string
+	if f.githubToken != "" || f.tokenFuncs["github"] != nil {
+		names = append(names, "github")
+	}
+	if f.gitlabToken != "" || f.tokenFuncs["gitlab"] != nil {
+		names = append(names, "gitlab")
+	}
+	if f.bitbucketUser != "" && f.bitbucketPass != "" {
+		names = append(names, "bitbucket")
+	}
+	return names
+}
+
 // GetProvider gets a provider client by name
 func (f *ProviderFactory) GetProvider(name string) (Provider, error) {
 	switch strings.ToLower(name) {
 	case "github":
-		if f.githubToken == "" {
+		token, err := f.token("github")
+		if err != nil {
+			return nil, err
+		}
+		if token == "" {
 			return nil, fmt.Errorf("GitHub token not configured")
 		}
-		return &GitHubProviderAdapter{client: NewGitHubClient(f.githubToken)}, nil
+		return &GitHubProviderAdapter{client: NewGitHubClient(token)}, nil
 	case "gitlab":
-		if f.gitlabToken == "" {
+		token, err := f.token("gitlab")
+		if err != nil {
+			return nil, err
+		}
+		if token == "" {
 			return nil, fmt.Errorf("GitLab token not configured")
 		}
-		return &GitLabProviderAdapter{client: NewGitLabClient(f.gitlabToken)}, nil
+		return &GitLabProviderAdapter{client: NewGitLabClient(token)}, nil
 	case "bitbucket":
 		if f.bitbucketUser == "" || f.bitbucketPass == "" {
 			return nil, fmt.Errorf("Bitbucket credentials not configured")
@@ -101,6 +158,11 @@ func ParseRepoURL(url string) (provider, owner, repo string, err error) {
 	// Remove .git suffix
 	url = strings.TrimSuffix(url, ".git")
 
+	// Normalize scp-like SSH remotes (git@github.com:owner/repo)
+	if !strings.Contains(url, "://") {
+		url = strings.Replace(url, ":", "/", 1)
+	}
+
 	if strings.Contains(url, "github.com") {
 		parts := strings.Split(url, "github.com/")
 		if len(parts) != 2 {
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"fmt"
 	"testing"
 )
 
@@ -40,6 +41,13 @@ func TestParseRepoURL(t *testing.T) {
 			repo:     "repo",
 			err:      false,
 		},
+		{
+			url:      "git@github.com:user/repo.git",
+			provider: "github",
+			owner:    "user",
+			repo:     "repo",
+			err:      false,
+		},
 		{
 			url:      "invalid-url",
 			provider: "",
@@ -70,3 +78,37 @@ func TestParseRepoURL(t *testing.T) {
 		})
 	}
 }
+
+func TestProviderFactoryTokenFunc(t *testing.T) {
+	factory := NewProviderFactory()
+	factory.SetGitHubToken("stale")
+
+	calls := 0
+	factory.SetTokenFunc("github", func() (string, error) {
+		calls++
+		return "fresh", nil
+	})
+
+	for i := 0; i < 2; i++ {
+		p, err := factory.GetProvider("github")
+		if err != nil {
+			t.Fatalf("GetProvider failed: %v", err)
+		}
+		if token := p.(*GitHubProviderAdapter).client.token; token != "fresh" {
+			t.Errorf("expected refreshed token, got %q", token)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected token func to be called for each client, got %d", calls)
+	}
+
+	factory.SetTokenFunc("gitlab", func() (string, error) {
+		return "", fmt.Errorf("refresh failed")
+	})
+	if _, err := factory.GetProvider("gitlab"); err == nil {
+		t.Errorf("expected refresh error to be returned")
+	}
+	if got := factory.ConfiguredProviders(); len(got) != 2 {
+		t.Errorf("expected github and gitlab configured, got %v", got)
+	}
+}
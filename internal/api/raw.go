@@ -0,0 +1,155 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// RawClient sends arbitrary authenticated requests to a provider's API,
+// reusing its configured credentials and base URL
+type RawClient interface {
+	BaseURL() string
+	Do(ctx context.Context, method, path string, body interface{}) (*http.Response, error)
+}
+
+// BaseURL returns the API base URL
+func (c *Client) BaseURL() string { return c.baseURL }
+
+// Do performs an authenticated request against the GitKraken API
+func (c *Client) Do(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
+	return c.doRequest(ctx, method, path, body)
+}
+
+// BaseURL returns the API base URL
+func (c *GitHubClient) BaseURL() string { return c.baseURL }
+
+// Do performs an authenticated request against the GitHub API
+func (c *GitHubClient) Do(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
+	return c.doRequest(ctx, method, path, body)
+}
+
+// BaseURL returns the API base URL
+func (c *GitLabClient) BaseURL() string { return c.baseURL }
+
+// Do performs an authenticated request against the GitLab API
+func (c *GitLabClient) Do(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
+	return c.doRequest(ctx, method, path, body)
+}
+
+// BaseURL returns the API base URL
+func (c *BitbucketClient) BaseURL() string { return c.baseURL }
+
+// Do performs an authenticated request against the Bitbucket API
+func (c *BitbucketClient) Do(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
+	return c.doRequest(ctx, method, path, body)
+}
+
+// RawClient returns a raw API client for a provider. "gitkraken" uses the
+// token stored by 'gk login'.
+func (f *ProviderFactory) RawClient(name string) (RawClient, error) {
+	switch strings.ToLower(name) {
+	case "gitkraken":
+		return NewClient("")
+	case "github", "gitlab", "bitbucket":
+		provider, err := f.GetProvider(name)
+		if err != nil {
+			return nil, err
+		}
+		switch p := provider.(type) {
+		case *GitHubProviderAdapter:
+			return p.client, nil
+		case *GitLabProviderAdapter:
+			return p.client, nil
+		case *BitbucketProviderAdapter:
+			return p.client, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown provider: %s (supported: github, gitlab, bitbucket, gitkraken)", name)
+}
+
+var linkNextRe = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// RawRequest sends a request and returns the response body. With paginate,
+// following pages are fetched through Link headers (GitHub, GitLab) or the
+// "next" field (Bitbucket) and their items are merged into a single JSON
+// array.
+func RawRequest(ctx context.Context, c RawClient, method, path string, body interface{}, paginate bool) ([]byte, error) {
+	if !strings.HasPrefix(path, "/") && !strings.HasPrefix(path, "http") {
+		path = "/" + path
+	}
+
+	var items []json.RawMessage
+	for {
+		resp, err := c.Do(ctx, method, strings.TrimPrefix(path, c.BaseURL()), body)
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+		if !paginate {
+			return data, nil
+		}
+
+		page, next, err := pageItems(data, resp.Header.Get("Link"))
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+		if next == "" {
+			break
+		}
+		if !strings.HasPrefix(next, c.BaseURL()) {
+			return nil, fmt.Errorf("refusing to follow pagination link outside %s: %s", c.BaseURL(), next)
+		}
+		path = next
+		body = nil
+	}
+
+	if items == nil {
+		items = []json.RawMessage{}
+	}
+	return json.Marshal(items)
+}
+
+// pageItems splits a page into its items and the URL of the next page
+func pageItems(data []byte, link string) ([]json.RawMessage, string, error) {
+	next := ""
+	if m := linkNextRe.FindStringSubmatch(link); m != nil {
+		next = m[1]
+	}
+
+	var array []json.RawMessage
+	if err := json.Unmarshal(data, &array); err == nil {
+		return array, next, nil
+	}
+
+	// Bitbucket pages list their items in values; GitHub search results
+	// are in items
+	var page struct {
+		Values []json.RawMessage `json:"values"`
+		Items  []json.RawMessage `json:"items"`
+		Next   string            `json:"next"`
+	}
+	if err := json.Unmarshal(data, &page); err != nil {
+		return nil, "", fmt.Errorf("cannot paginate non-JSON response: %w", err)
+	}
+	list := page.Values
+	if list == nil {
+		list = page.Items
+	}
+	if list == nil {
+		return nil, "", fmt.Errorf("cannot paginate response: expected a JSON array, a 'values' list or an 'items' list")
+	}
+	if page.Next != "" {
+		next = page.Next
+	}
+	return list, next, nil
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRawRequestPaginatesLinkHeaders(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token secret" {
+			t.Errorf("expected token auth, got %q", r.Header.Get("Authorization"))
+		}
+		if r.URL.Query().Get("page") == "2" {
+			w.Write([]byte(`[{"id": 3}]`))
+			return
+		}
+		w.Header().Set("Link", `<`+server.URL+`/items?page=2>; rel="next", <`+server.URL+`/items?page=2>; rel="last"`)
+		w.Write([]byte(`[{"id": 1}, {"id": 2}]`))
+	}))
+	defer server.Close()
+
+	client := NewGitHubClient("secret")
+	client.baseURL = server.URL
+
+	data, err := RawRequest(context.Background(), client, "GET", "items", nil, true)
+	if err != nil {
+		t.Fatalf("RawRequest failed: %v", err)
+	}
+	if string(data) != `[{"id":1},{"id":2},{"id":3}]` {
+		t.Errorf("unexpected merged pages: %s", data)
+	}
+}
+
+func TestPageItemsBitbucketNext(t *testing.T) {
+	items, next, err := pageItems([]byte(`{"values": [{"id": 1}], "next": "https://api.bitbucket.org/2.0/x?page=2"}`), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || next != "https://api.bitbucket.org/2.0/x?page=2" {
+		t.Errorf("unexpected page: %d items, next %q", len(items), next)
+	}
+}
+
+func TestPageItemsGitHubSearch(t *testing.T) {
+	link := `<https://api.github.com/search/issues?q=x&page=2>; rel="next"`
+	items, next, err := pageItems([]byte(`{"total_count": 3, "incomplete_results": false, "items": [{"id": 1}, {"id": 2}]}`), link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || next != "https://api.github.com/search/issues?q=x&page=2" {
+		t.Errorf("unexpected page: %d items, next %q", len(items), next)
+	}
+}
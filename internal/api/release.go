@@ -0,0 +1,165 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Release is a unified release structure. On Bitbucket, which has no
+// releases, it represents a tag.
+type Release struct {
+	Provider    string
+	Repo        string
+	TagName     string
+	Name        string
+	Body        string
+	URL         string
+	Author      string
+	Target      string
+	Draft       bool
+	Prerelease  bool
+	CreatedAt   string
+	PublishedAt string
+	Assets      []ReleaseAsset
+}
+
+// ReleaseAsset is a file attached to a release
+type ReleaseAsset struct {
+	Name string
+	URL  string
+	Size int64
+}
+
+// ReleaseOptions describes a release to create
+type ReleaseOptions struct {
+	TagName    string
+	Name       string
+	Body       string
+	Target     string // branch or commit SHA the tag is created from
+	Draft      bool
+	Prerelease bool
+	Assets     []string // local file paths to upload
+}
+
+// ReleaseManager is implemented by providers that support releases
+type ReleaseManager interface {
+	ListReleases(owner, repo string) ([]Release, error)
+	GetRelease(owner, repo, tag string) (*Release, error)
+	CreateRelease(owner, repo string, opts ReleaseOptions) (*Release, error)
+}
+
+const (
+	// mergedPageSize is how many merged pull requests are requested per page
+	mergedPageSize = 100
+	// bitbucketMergedPageSize is the largest page Bitbucket allows
+	bitbucketMergedPageSize = 50
+	// maxMergedPullRequests bounds how many merged pull requests are fetched
+	// for release notes, matching the most GitHub search returns
+	maxMergedPullRequests = 1000
+)
+
+// MergedPullRequestLister is implemented by providers that can list the pull
+// requests merged into a repository since a point in time
+type MergedPullRequestLister interface {
+	ListMergedPullRequests(owner, repo string, since time.Time) ([]PullRequest, error)
+}
+
+// GenerateReleaseNotes builds release notes from the pull requests merged
+// into a repository after since. A zero since includes every merged PR.
+func GenerateReleaseNotes(p Provider, owner, repo string, since time.Time) (string, error) {
+	lister, ok := p.(MergedPullRequestLister)
+	if !ok {
+		return "", fmt.Errorf("release notes are not supported for %s", p.GetName())
+	}
+
+	prs, err := lister.ListMergedPullRequests(owner, repo, since)
+	if err != nil {
+		return "", fmt.Errorf("failed to list merged pull requests: %w", err)
+	}
+
+	var merged []PullRequest
+	for _, pr := range prs {
+		if pr.MergedAt == "" {
+			continue
+		}
+		mergedAt, err := time.Parse(time.RFC3339, pr.MergedAt)
+		if err != nil || !mergedAt.After(since) {
+			continue
+		}
+		merged = append(merged, pr)
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		return merged[i].MergedAt < merged[j].MergedAt
+	})
+
+	var b strings.Builder
+	b.WriteString("## What's Changed\n\n")
+	if len(merged) == 0 {
+		b.WriteString("No pull requests were merged since the previous release.\n")
+		return b.String(), nil
+	}
+	for _, pr := range merged {
+		fmt.Fprintf(&b, "- %s (#%d)", pr.Title, pr.Number)
+		if pr.Author != "" {
+			fmt.Fprintf(&b, " by @%s", pr.Author)
+		}
+		b.WriteString("\n")
+	}
+	return b.String(), nil
+}
+
+// LatestRelease returns the most recently created published release, or nil
+func LatestRelease(releases []Release) *Release {
+	var latest *Release
+	for i := range releases {
+		r := &releases[i]
+		if r.Draft {
+			continue
+		}
+		if latest == nil || releaseTime(r).After(releaseTime(latest)) {
+			latest = r
+		}
+	}
+	return latest
+}
+
+func releaseTime(r *Release) time.Time {
+	ts := r.PublishedAt
+	if ts == "" {
+		ts = r.CreatedAt
+	}
+	t, _ := time.Parse(time.RFC3339, ts)
+	return t
+}
+
+// multipartFile builds a multipart/form-data body holding the file at path
+// under field, returning the body and its content type
+func multipartFile(field, path string) (*bytes.Buffer, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to open asset: %w", err)
+	}
+	defer f.Close()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(field, filepath.Base(path))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to build upload: %w", err)
+	}
+	if _, err := io.Copy(part, f); err != nil {
+		return nil, "", fmt.Errorf("failed to read asset: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to build upload: %w", err)
+	}
+	return body, writer.FormDataContentType(), nil
+}
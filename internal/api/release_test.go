@@ -0,0 +1,108 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeProvider struct {
+	name string
+	prs  []PullRequest
+}
+
+func (f *fakeProvider) GetName() string { return f.name }
+
+func (f *fakeProvider) ListPullRequests(owner, repo, state string) ([]PullRequest, error) {
+	return f.prs, nil
+}
+
+func (f *fakeProvider) GetPullRequest(owner, repo string, number int) (*PullRequest, error) {
+	return nil, ErrNotSupported
+}
+
+func (f *fakeProvider) ListIssues(owner, repo, state string) ([]Issue, error) {
+	return nil, nil
+}
+
+func (f *fakeProvider) ListMergedPullRequests(owner, repo string, since time.Time) ([]PullRequest, error) {
+	return f.prs, nil
+}
+
+func TestGenerateReleaseNotes(t *testing.T) {
+	p := &fakeProvider{name: "github", prs: []PullRequest{
+		{Number: 3, Title: "Add search", Author: "alice", MergedAt: "2024-03-02T10:00:00Z"},
+		{Number: 2, Title: "Closed without merge", Author: "bob"},
+		{Number: 1, Title: "Fix login", Author: "carol", MergedAt: "2024-02-01T10:00:00Z"},
+		{Number: 4, Title: "Add inbox", MergedAt: "2024-03-01T10:00:00Z"},
+	}}
+
+	since, _ := time.Parse(time.RFC3339, "2024-02-15T00:00:00Z")
+	notes, err := GenerateReleaseNotes(p, "acme", "api", since)
+	if err != nil {
+		t.Fatalf("Failed to generate notes: %v", err)
+	}
+
+	want := "## What's Changed\n\n- Add inbox (#4)\n- Add search (#3) by @alice\n"
+	if notes != want {
+		t.Errorf("Expected %q, got %q", want, notes)
+	}
+	if strings.Contains(notes, "Fix login") {
+		t.Errorf("Expected PR merged before the previous release to be excluded")
+	}
+}
+
+func TestLatestReleaseSkipsDrafts(t *testing.T) {
+	releases := []Release{
+		{TagName: "v1.0.0", PublishedAt: "2024-01-01T00:00:00Z"},
+		{TagName: "v1.2.0", CreatedAt: "2024-03-01T00:00:00Z", Draft: true},
+		{TagName: "v1.1.0", PublishedAt: "2024-02-01T00:00:00Z"},
+	}
+	if latest := LatestRelease(releases); latest == nil || latest.TagName != "v1.1.0" {
+		t.Errorf("Expected v1.1.0, got %+v", latest)
+	}
+}
+
+func TestGitHubListMergedPullRequestsFollowsPages(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("q"))
+		page := r.URL.Query().Get("page")
+		count := mergedPageSize
+		if page == "2" {
+			count = 1
+		}
+		var items []string
+		for i := 0; i < count; i++ {
+			items = append(items, fmt.Sprintf(`{"number": %s%d, "pull_request": {"merged_at": "2024-03-01T10:00:00Z"}}`, page, i))
+		}
+		fmt.Fprintf(w, `{"total_count": %d, "items": [%s]}`, mergedPageSize+1, strings.Join(items, ","))
+	}))
+	defer server.Close()
+
+	client := NewGitHubClient("token")
+	client.baseURL = server.URL
+	adapter := &GitHubProviderAdapter{client: client}
+
+	since, _ := time.Parse(time.RFC3339, "2024-02-15T00:00:00Z")
+	prs, err := adapter.ListMergedPullRequests("acme", "api", since)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(queries) != 2 {
+		t.Fatalf("Expected 2 pages to be requested, got %d", len(queries))
+	}
+	if want := "repo:acme/api is:pr is:merged merged:>2024-02-15T00:00:00Z"; queries[0] != want {
+		t.Errorf("Expected query %q, got %q", want, queries[0])
+	}
+	if len(prs) != mergedPageSize+1 {
+		t.Errorf("Expected %d pull requests, got %d", mergedPageSize+1, len(prs))
+	}
+	if prs[0].MergedAt != "2024-03-01T10:00:00Z" {
+		t.Errorf("Expected merge time from the search result, got %q", prs[0].MergedAt)
+	}
+}
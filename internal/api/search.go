@@ -0,0 +1,202 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SearchQuery describes a cross-provider pull request or issue search
+type SearchQuery struct {
+	Text     string
+	Author   string
+	Labels   []string
+	Involves string
+	Repo     string // owner/name
+	Org      string // GitHub org, GitLab group or Bitbucket workspace
+	State    string // open, closed, all
+}
+
+// Searcher is implemented by providers that support searching pull requests
+// and issues across repositories
+type Searcher interface {
+	SearchPullRequests(q SearchQuery) ([]PullRequest, error)
+	SearchIssues(q SearchQuery) ([]Issue, error)
+}
+
+// githubSearchQuery translates q into GitHub search syntax. kind is "pr" or "issue".
+func githubSearchQuery(q SearchQuery, kind string) string {
+	terms := []string{}
+	if q.Text != "" {
+		terms = append(terms, q.Text)
+	}
+	terms = append(terms, "is:"+kind)
+	switch q.State {
+	case "", "open":
+		terms = append(terms, "is:open")
+	case "closed":
+		terms = append(terms, "is:closed")
+	}
+	if q.Author != "" {
+		terms = append(terms, "author:"+q.Author)
+	}
+	for _, label := range q.Labels {
+		terms = append(terms, "label:"+quoteSearchTerm(label))
+	}
+	if q.Involves != "" {
+		terms = append(terms, "involves:"+q.Involves)
+	}
+	if q.Repo != "" {
+		terms = append(terms, "repo:"+q.Repo)
+	}
+	if q.Org != "" {
+		terms = append(terms, "org:"+q.Org)
+	}
+	return strings.Join(terms, " ")
+}
+
+func quoteSearchTerm(s string) string {
+	if strings.ContainsAny(s, " \t") {
+		return `"` + s + `"`
+	}
+	return s
+}
+
+// gitlabSearchPath returns the endpoint for searching merge requests or
+// issues, scoped to a project or group when q asks for one. resource is
+// "merge_requests" or "issues".
+func gitlabSearchPath(q SearchQuery, resource string) string {
+	switch {
+	case q.Repo != "":
+		return fmt.Sprintf("/projects/%s/%s", url.PathEscape(q.Repo), resource)
+	case q.Org != "":
+		return fmt.Sprintf("/groups/%s/%s", url.PathEscape(q.Org), resource)
+	default:
+		return "/" + resource
+	}
+}
+
+// gitlabSearchParams translates q into GitLab list parameters. GitLab has no
+// "involves" filter, so each returned parameter set is queried separately
+// and the results merged: one per role the user can have.
+func gitlabSearchParams(q SearchQuery, resource string) []url.Values {
+	base := url.Values{}
+	base.Set("scope", "all")
+	base.Set("per_page", "100")
+	switch q.State {
+	case "", "open":
+		base.Set("state", "opened")
+	case "closed":
+		base.Set("state", "closed")
+	}
+	if q.Text != "" {
+		base.Set("search", q.Text)
+	}
+	if q.Author != "" {
+		base.Set("author_username", q.Author)
+	}
+	if len(q.Labels) > 0 {
+		base.Set("labels", strings.Join(q.Labels, ","))
+	}
+
+	if q.Involves == "" {
+		return []url.Values{base}
+	}
+
+	roles := []string{"author_username", "assignee_username"}
+	if resource == "merge_requests" {
+		roles = append(roles, "reviewer_username")
+	}
+
+	var sets []url.Values
+	for _, role := range roles {
+		if q.Author != "" && role == "author_username" && q.Author != q.Involves {
+			continue
+		}
+		params := url.Values{}
+		for k, v := range base {
+			params[k] = append([]string(nil), v...)
+		}
+		params.Set(role, q.Involves)
+		sets = append(sets, params)
+	}
+	return sets
+}
+
+// bitbucketSearchFilter translates q into a Bitbucket query language (BBQL)
+// filter. kind is "pr" or "issue".
+func bitbucketSearchFilter(q SearchQuery, kind string) string {
+	var clauses []string
+	if q.Text != "" {
+		clauses = append(clauses, fmt.Sprintf(`title ~ %q`, q.Text))
+	}
+
+	switch kind {
+	case "pr":
+		switch q.State {
+		case "", "open":
+			clauses = append(clauses, `state = "OPEN"`)
+		case "closed":
+			clauses = append(clauses, `(state = "MERGED" OR state = "DECLINED")`)
+		}
+		if q.Author != "" {
+			clauses = append(clauses, fmt.Sprintf(`author.nickname = %q`, q.Author))
+		}
+		if q.Involves != "" {
+			clauses = append(clauses, fmt.Sprintf(`(author.nickname = %q OR reviewers.nickname = %q)`, q.Involves, q.Involves))
+		}
+	case "issue":
+		switch q.State {
+		case "", "open":
+			clauses = append(clauses, `(state = "new" OR state = "open")`)
+		case "closed":
+			clauses = append(clauses, `(state = "resolved" OR state = "closed" OR state = "invalid" OR state = "duplicate" OR state = "wontfix")`)
+		}
+		if q.Author != "" {
+			clauses = append(clauses, fmt.Sprintf(`reporter.nickname = %q`, q.Author))
+		}
+		if q.Involves != "" {
+			clauses = append(clauses, fmt.Sprintf(`(reporter.nickname = %q OR assignee.nickname = %q)`, q.Involves, q.Involves))
+		}
+		// Bitbucket issues have no labels; components are the closest match
+		for _, label := range q.Labels {
+			clauses = append(clauses, fmt.Sprintf(`component.name = %q`, label))
+		}
+	}
+
+	return strings.Join(clauses, " AND ")
+}
+
+// repoFromWebURL extracts the owner/name path from a GitLab web URL such as
+// https://gitlab.com/group/sub/project/-/merge_requests/1
+func repoFromWebURL(webURL string) string {
+	u, err := url.Parse(webURL)
+	if err != nil {
+		return ""
+	}
+	path := strings.TrimPrefix(u.Path, "/")
+	if i := strings.Index(path, "/-/"); i >= 0 {
+		return path[:i]
+	}
+	return path
+}
+
+// SortPullRequestsByUpdated sorts pull requests most recently updated first
+func SortPullRequestsByUpdated(prs []PullRequest) {
+	sort.SliceStable(prs, func(i, j int) bool {
+		ti, _ := time.Parse(time.RFC3339, prs[i].UpdatedAt)
+		tj, _ := time.Parse(time.RFC3339, prs[j].UpdatedAt)
+		return ti.After(tj)
+	})
+}
+
+// SortIssuesByUpdated sorts issues most recently updated first
+func SortIssuesByUpdated(issues []Issue) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		ti, _ := time.Parse(time.RFC3339, issues[i].UpdatedAt)
+		tj, _ := time.Parse(time.RFC3339, issues[j].UpdatedAt)
+		return ti.After(tj)
+	})
+}
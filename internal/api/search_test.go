@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestGitHubSearchQuery(t *testing.T) {
+	q := SearchQuery{
+		Text:     "auth",
+		Author:   "alice",
+		Labels:   []string{"bug", "needs review"},
+		Involves: "bob",
+		Org:      "acme",
+	}
+
+	got := githubSearchQuery(q, "pr")
+	want := `auth is:pr is:open author:alice label:bug label:"needs review" involves:bob org:acme`
+	if got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+
+	q = SearchQuery{Repo: "acme/api", State: "all"}
+	got = githubSearchQuery(q, "issue")
+	want = "is:issue repo:acme/api"
+	if got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestGitLabSearch(t *testing.T) {
+	q := SearchQuery{Text: "auth", Labels: []string{"bug", "p1"}, Org: "acme/platform"}
+	if path := gitlabSearchPath(q, "merge_requests"); path != "/groups/acme%2Fplatform/merge_requests" {
+		t.Errorf("Unexpected path %s", path)
+	}
+
+	sets := gitlabSearchParams(q, "merge_requests")
+	if len(sets) != 1 {
+		t.Fatalf("Expected 1 parameter set, got %d", len(sets))
+	}
+	if sets[0].Get("search") != "auth" || sets[0].Get("labels") != "bug,p1" || sets[0].Get("state") != "opened" {
+		t.Errorf("Unexpected params %v", sets[0])
+	}
+
+	q = SearchQuery{Involves: "bob"}
+	sets = gitlabSearchParams(q, "merge_requests")
+	if len(sets) != 3 {
+		t.Fatalf("Expected one parameter set per role, got %d", len(sets))
+	}
+	if sets[2].Get("reviewer_username") != "bob" {
+		t.Errorf("Expected reviewer_username=bob, got %v", sets[2])
+	}
+	if len(gitlabSearchParams(q, "issues")) != 2 {
+		t.Errorf("Issues have no reviewers; expected 2 parameter sets")
+	}
+}
+
+func TestBitbucketSearchFilter(t *testing.T) {
+	q := SearchQuery{Text: "auth", Author: "alice"}
+	got := bitbucketSearchFilter(q, "pr")
+	want := `title ~ "auth" AND state = "OPEN" AND author.nickname = "alice"`
+	if got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestRepoFromWebURL(t *testing.T) {
+	got := repoFromWebURL("https://gitlab.com/group/sub/project/-/merge_requests/12")
+	if got != "group/sub/project" {
+		t.Errorf("Expected group/sub/project, got %s", got)
+	}
+}
+
+func TestBitbucketSearchPullRequestsRejectsLabels(t *testing.T) {
+	client := NewBitbucketClient("user", "pass")
+	_, err := client.SearchPullRequests(context.Background(), SearchQuery{Repo: "acme/api", Labels: []string{"bug"}})
+	if err == nil || !strings.Contains(err.Error(), "label") {
+		t.Errorf("Expected an error for a label filter, got %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// TokenInfo describes a credential as reported by the provider
+type TokenInfo struct {
+	Scopes    []string // nil when the provider does not report scopes
+	ExpiresAt string
+}
+
+// Identity is the account behind a provider's credentials
+type Identity struct {
+	Provider  string
+	Login     string
+	Name      string
+	Scopes    []string // nil when unknown
+	ExpiresAt string
+}
+
+// Validator is implemented by providers that can check their credentials
+type Validator interface {
+	Validate() (*Identity, error)
+}
+
+// requiredScopes lists, per provider, the scopes needed for pull requests,
+// issues and checks. Each entry is satisfied by any one of its scopes.
+var requiredScopes = map[string][][]string{
+	"github":    {{"repo"}},
+	"gitlab":    {{"api", "read_api"}},
+	"bitbucket": {{"pullrequest", "pullrequest:write"}, {"issue", "issue:write"}},
+}
+
+// MissingScopes returns the required scopes a credential lacks. Unknown
+// scopes (nil) are never reported as missing.
+func MissingScopes(provider string, scopes []string) []string {
+	if scopes == nil {
+		return nil
+	}
+	have := make(map[string]bool, len(scopes))
+	for _, s := range scopes {
+		have[strings.ToLower(s)] = true
+	}
+
+	var missing []string
+	for _, anyOf := range requiredScopes[provider] {
+		ok := false
+		for _, s := range anyOf {
+			if have[s] {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			missing = append(missing, strings.Join(anyOf, " or "))
+		}
+	}
+	return missing
+}
+
+// scopesFromHeader parses a comma separated scope header, returning nil if
+// the header is absent
+func scopesFromHeader(h http.Header, name string) []string {
+	values, ok := h[http.CanonicalHeaderKey(name)]
+	if !ok {
+		return nil
+	}
+	scopes := []string{}
+	for _, value := range values {
+		for _, s := range strings.Split(value, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				scopes = append(scopes, s)
+			}
+		}
+	}
+	sort.Strings(scopes)
+	return scopes
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestGitHubValidate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/user" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("X-OAuth-Scopes", "read:org, public_repo")
+		w.Header().Set("GitHub-Authentication-Token-Expiration", "2030-01-31 12:00:00 UTC")
+		w.Write([]byte(`{"login": "alice", "name": "Alice"}`))
+	}))
+	defer server.Close()
+
+	client := NewGitHubClient("token")
+	client.baseURL = server.URL
+	id, err := (&GitHubProviderAdapter{client: client}).Validate()
+	if err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	if id.Login != "alice" || id.ExpiresAt != "2030-01-31T12:00:00Z" {
+		t.Errorf("unexpected identity: %+v", id)
+	}
+	if !reflect.DeepEqual(id.Scopes, []string{"public_repo", "read:org"}) {
+		t.Errorf("unexpected scopes: %v", id.Scopes)
+	}
+	if missing := MissingScopes("github", id.Scopes); !reflect.DeepEqual(missing, []string{"repo"}) {
+		t.Errorf("expected repo to be missing, got %v", missing)
+	}
+}
+
+func TestMissingScopes(t *testing.T) {
+	if missing := MissingScopes("gitlab", []string{"read_api"}); missing != nil {
+		t.Errorf("read_api should satisfy gitlab, got %v", missing)
+	}
+	if missing := MissingScopes("bitbucket", []string{"pullrequest"}); !reflect.DeepEqual(missing, []string{"issue or issue:write"}) {
+		t.Errorf("unexpected bitbucket result: %v", missing)
+	}
+	if missing := MissingScopes("github", nil); missing != nil {
+		t.Errorf("unknown scopes should not be reported missing, got %v", missing)
+	}
+}
@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const callbackPath = "/callback"
+
+// callbackPage is shown in the browser once the callback is handled
+var callbackPage = template.Must(template.New("callback").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>GitKraken CLI</title>
+<style>body{font-family:sans-serif;margin:4em auto;max-width:36em;text-align:center}h1{color:{{if .Success}}#1a7f37{{else}}#cf222e{{end}}}</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Message}}</p>
+{{if .Detail}}<p><code>{{.Detail}}</code></p>{{end}}
+<p>You can close this window and return to the terminal.</p>
+</body>
+</html>
+`))
+
+type callbackPageData struct {
+	Success bool
+	Title   string
+	Message string
+	Detail  string
+}
+
+// callbackResult is the outcome of the OAuth redirect
+type callbackResult struct {
+	code string
+	err  error
+}
+
+// callbackServer receives the OAuth redirect on a random loopback port
+type callbackServer struct {
+	state    string
+	listener net.Listener
+	server   *http.Server
+	result   chan callbackResult
+	once     sync.Once
+}
+
+// newCallbackServer starts a callback server on 127.0.0.1 that accepts only
+// redirects carrying the given state
+func newCallbackServer(state string) (*callbackServer, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to start callback server: %w", err)
+	}
+
+	s := &callbackServer{
+		state:    state,
+		listener: listener,
+		result:   make(chan callbackResult, 1),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(callbackPath, s.handleCallback)
+	s.server = &http.Server{
+		Handler:      mux,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			s.finish(callbackResult{err: err})
+		}
+	}()
+
+	return s, nil
+}
+
+// RedirectURL returns the redirect URI for the port the server listens on
+func (s *callbackServer) RedirectURL() string {
+	return fmt.Sprintf("http://%s%s", s.listener.Addr().String(), callbackPath)
+}
+
+// Wait blocks until the callback delivers a code or an error, the timeout
+// passes or ctx is done
+func (s *callbackServer) Wait(ctx context.Context, timeout time.Duration) (string, error) {
+	select {
+	case r := <-s.result:
+		return r.code, r.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case <-time.After(timeout):
+		return "", fmt.Errorf("authentication timeout")
+	}
+}
+
+// Close shuts the server down, letting the final page finish rendering
+func (s *callbackServer) Close() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	s.server.Shutdown(ctx)
+}
+
+func (s *callbackServer) finish(r callbackResult) {
+	s.once.Do(func() {
+		s.result <- r
+	})
+}
+
+func (s *callbackServer) handleCallback(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	// Requests without a matching state did not come from our authorization
+	// request; reject them without ending the flow
+	if subtle.ConstantTimeCompare([]byte(q.Get("state")), []byte(s.state)) != 1 {
+		renderCallbackPage(w, http.StatusBadRequest, callbackPageData{
+			Title:   "Invalid request",
+			Message: "This request does not match the login in progress.",
+		})
+		return
+	}
+
+	if oauthErr := q.Get("error"); oauthErr != "" {
+		detail := oauthErr
+		if desc := q.Get("error_description"); desc != "" {
+			detail += ": " + desc
+		}
+		renderCallbackPage(w, http.StatusBadRequest, callbackPageData{
+			Title:   "Authentication failed",
+			Message: "The authorization server returned an error.",
+			Detail:  detail,
+		})
+		s.finish(callbackResult{err: fmt.Errorf("authorization failed: %s", detail)})
+		return
+	}
+
+	code := q.Get("code")
+	if code == "" {
+		renderCallbackPage(w, http.StatusBadRequest, callbackPageData{
+			Title:   "Authentication failed",
+			Message: "The authorization server did not return an authorization code.",
+		})
+		s.finish(callbackResult{err: fmt.Errorf("missing authorization code")})
+		return
+	}
+
+	renderCallbackPage(w, http.StatusOK, callbackPageData{
+		Success: true,
+		Title:   "Success!",
+		Message: "You are now logged in to the GitKraken CLI.",
+	})
+	s.finish(callbackResult{code: code})
+}
+
+func renderCallbackPage(w http.ResponseWriter, status int, data callbackPageData) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	callbackPage.Execute(w, data)
+}
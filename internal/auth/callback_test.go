@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCallbackServerIgnoresStrayRequests(t *testing.T) {
+	s, err := newCallbackServer("expected-state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if !strings.HasPrefix(s.RedirectURL(), "http://127.0.0.1:") {
+		t.Errorf("expected loopback redirect URL, got %s", s.RedirectURL())
+	}
+	base := strings.TrimSuffix(s.RedirectURL(), callbackPath)
+
+	// Neither a favicon request nor a forged callback ends the flow
+	for _, u := range []string{base + "/favicon.ico", s.RedirectURL() + "?code=evil&state=wrong"} {
+		resp, err := http.Get(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode < 400 {
+			t.Errorf("%s: expected error status, got %d", u, resp.StatusCode)
+		}
+	}
+
+	resp, err := http.Get(s.RedirectURL() + "?code=good&state=expected-state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), "Success!") {
+		t.Errorf("expected success page, got:\n%s", body)
+	}
+
+	code, err := s.Wait(context.Background(), time.Second)
+	if err != nil || code != "good" {
+		t.Errorf("expected code 'good', got %q (%v)", code, err)
+	}
+}
+
+func TestCallbackServerReportsOAuthError(t *testing.T) {
+	s, err := newCallbackServer("st")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	resp, err := http.Get(s.RedirectURL() + "?state=st&error=access_denied&error_description=User+said+%3Cno%3E")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), "access_denied: User said &lt;no&gt;") {
+		t.Errorf("expected escaped OAuth error on page, got:\n%s", body)
+	}
+
+	if _, err := s.Wait(context.Background(), time.Second); err == nil || !strings.Contains(err.Error(), "access_denied") {
+		t.Errorf("expected access_denied error, got %v", err)
+	}
+}
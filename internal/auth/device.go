@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+const deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// devicePollUnit is the unit of the polling interval returned by the
+// server, shortened in tests
+var devicePollUnit = time.Second
+
+// deviceTokenResponse is a token endpoint response during device polling
+type deviceTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Scope            string `json:"scope"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// StartDeviceFlow runs the OAuth 2.0 Device Authorization Grant, for
+// sessions without a local browser, and stores the resulting tokens
+func StartDeviceFlow(out io.Writer) error {
+	token, err := deviceAuthorize(context.Background(), currentConfig(), out)
+	if err != nil {
+		return err
+	}
+
+	if err := saveToken(token); err != nil {
+		return fmt.Errorf("failed to save token: %w", err)
+	}
+
+	fmt.Fprintln(out, "✓ Successfully authenticated!")
+	return nil
+}
+
+// deviceAuthorize requests a device code, shows the user where to enter it
+// and polls the token endpoint until access is granted or denied
+func deviceAuthorize(ctx context.Context, conf *oauth2.Config, out io.Writer) (*oauth2.Token, error) {
+	da, err := conf.DeviceAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start device authorization: %w", err)
+	}
+
+	fmt.Fprintf(out, "First copy your one-time code: %s\n", da.UserCode)
+	fmt.Fprintf(out, "Then open %s in a browser and enter the code.\n", da.VerificationURI)
+	if da.VerificationURIComplete != "" {
+		fmt.Fprintf(out, "Or open this URL directly: %s\n", da.VerificationURIComplete)
+	}
+	fmt.Fprintln(out, "Waiting for authorization...")
+
+	if !da.Expiry.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, da.Expiry)
+		defer cancel()
+	}
+
+	// Clients must default to 5 seconds when no interval is given (RFC 8628)
+	interval := time.Duration(da.Interval)
+	if interval <= 0 {
+		interval = 5
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("device code expired before authorization completed")
+		case <-time.After(interval * devicePollUnit):
+		}
+
+		resp, err := pollDeviceToken(ctx, conf, da.DeviceCode)
+		if err != nil {
+			return nil, err
+		}
+
+		switch resp.Error {
+		case "":
+			token := &oauth2.Token{
+				AccessToken:  resp.AccessToken,
+				RefreshToken: resp.RefreshToken,
+				TokenType:    resp.TokenType,
+			}
+			token = token.WithExtra(map[string]interface{}{"scope": resp.Scope})
+			if resp.ExpiresIn > 0 {
+				token.Expiry = time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second)
+			}
+			return token, nil
+		case "authorization_pending":
+			// Keep polling
+		case "slow_down":
+			// The interval must grow by 5 seconds for this and every
+			// later request (RFC 8628 section 3.5)
+			interval += 5
+		case "access_denied":
+			return nil, fmt.Errorf("authorization was denied")
+		case "expired_token":
+			return nil, fmt.Errorf("device code expired before authorization completed")
+		default:
+			if resp.ErrorDescription != "" {
+				return nil, fmt.Errorf("device authorization failed: %s: %s", resp.Error, resp.ErrorDescription)
+			}
+			return nil, fmt.Errorf("device authorization failed: %s", resp.Error)
+		}
+	}
+}
+
+// pollDeviceToken makes a single device code token request
+func pollDeviceToken(ctx context.Context, conf *oauth2.Config, deviceCode string) (*deviceTokenResponse, error) {
+	form := url.Values{
+		"grant_type":  {deviceCodeGrantType},
+		"device_code": {deviceCode},
+		"client_id":   {conf.ClientID},
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", conf.Endpoint.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result deviceTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode token response (%d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode >= 400 && result.Error == "" {
+		return nil, fmt.Errorf("token request failed with status %d", resp.StatusCode)
+	}
+	return &result, nil
+}
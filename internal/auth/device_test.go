@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeviceAuthorizeHandlesPendingAndSlowDown(t *testing.T) {
+	devicePollUnit = time.Millisecond
+	defer func() { devicePollUnit = time.Second }()
+
+	polls := 0
+	var lastPoll time.Time
+	mux := http.NewServeMux()
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"device_code":      "dev123",
+			"user_code":        "ABCD-EFGH",
+			"verification_uri": "https://example.com/device",
+			"expires_in":       600,
+			"interval":         1,
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("grant_type") != deviceCodeGrantType || r.Form.Get("device_code") != "dev123" {
+			t.Errorf("unexpected token request: %v", r.Form)
+		}
+		polls++
+		w.Header().Set("Content-Type", "application/json")
+		switch polls {
+		case 1:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": "authorization_pending"}`))
+		case 2:
+			lastPoll = time.Now()
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": "slow_down"}`))
+		default:
+			// Interval grew from 1 to 6 units after slow_down
+			if time.Since(lastPoll) < 6*time.Millisecond {
+				t.Errorf("expected polling to slow down, waited %v", time.Since(lastPoll))
+			}
+			w.Write([]byte(`{"access_token": "access", "refresh_token": "refresh", "expires_in": 3600}`))
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	s := Settings{ClientID: "test-client", TokenURL: server.URL + "/token", DeviceAuthURL: server.URL + "/device"}
+	var out bytes.Buffer
+	token, err := deviceAuthorize(context.Background(), s.oauth2Config(), &out)
+	if err != nil {
+		t.Fatalf("deviceAuthorize failed: %v", err)
+	}
+	if token.AccessToken != "access" || token.Expiry.IsZero() {
+		t.Errorf("unexpected token: %+v", token)
+	}
+	if polls != 3 {
+		t.Errorf("expected 3 polls, got %d", polls)
+	}
+	if !strings.Contains(out.String(), "ABCD-EFGH") || !strings.Contains(out.String(), "https://example.com/device") {
+		t.Errorf("expected user code and verification URL in output, got:\n%s", out.String())
+	}
+}
+
+func TestDeviceAuthorizeAccessDenied(t *testing.T) {
+	devicePollUnit = time.Millisecond
+	defer func() { devicePollUnit = time.Second }()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"device_code": "dev", "user_code": "X", "verification_uri": "https://example.com", "interval": 1}`))
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error": "access_denied"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	s := Settings{ClientID: "c", TokenURL: server.URL + "/token", DeviceAuthURL: server.URL + "/device"}
+	if _, err := deviceAuthorize(context.Background(), s.oauth2Config(), &bytes.Buffer{}); err == nil || !strings.Contains(err.Error(), "denied") {
+		t.Errorf("expected access denied error, got %v", err)
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"fmt"
-	"net/http"
 	"os"
 	"os/exec"
 	"runtime"
@@ -16,27 +15,87 @@ import (
 )
 
 const (
-	// GitKraken OAuth endpoints (placeholder - actual endpoints needed)
-	authURL  = "https://app.gitkraken.com/oauth/authorize"
-	tokenURL = "https://app.gitkraken.com/oauth/token"
-	// Note: These are placeholder URLs. Actual GitKraken OAuth endpoints need to be configured
+	// Default GitKraken OAuth endpoints and public client ID
+	DefaultAuthURL       = "https://app.gitkraken.com/oauth/authorize"
+	DefaultTokenURL      = "https://app.gitkraken.com/oauth/token"
+	DefaultDeviceAuthURL = "https://app.gitkraken.com/oauth/device/code"
+	DefaultRevokeURL     = "https://app.gitkraken.com/oauth/revoke"
+	DefaultClientID      = "gk-cli"
 )
 
+// Settings configures the OAuth client. The CLI is a public client: it has
+// no client secret and protects the authorization code with PKCE instead.
+type Settings struct {
+	ClientID      string
+	AuthURL       string
+	TokenURL      string
+	DeviceAuthURL string
+	RevokeURL     string
+	Scopes        []string
+}
+
 var (
 	oauthConfig *oauth2.Config
-	state       string
+	revokeURL   string
 )
 
+// LoadSettings returns the OAuth settings from the defaults, overridden by
+// the oauth section of the config file and then by the GITKRAKEN_CLIENT_ID,
+// GITKRAKEN_AUTH_URL, GITKRAKEN_TOKEN_URL, GITKRAKEN_DEVICE_AUTH_URL and
+// GITKRAKEN_REVOKE_URL environment variables
+func LoadSettings() Settings {
+	s := Settings{
+		ClientID:      DefaultClientID,
+		AuthURL:       DefaultAuthURL,
+		TokenURL:      DefaultTokenURL,
+		DeviceAuthURL: DefaultDeviceAuthURL,
+		RevokeURL:     DefaultRevokeURL,
+		Scopes:        []string{"read", "write"},
+	}
+
+	cfg := config.Get().OAuth
+	override(&s.ClientID, cfg.ClientID, os.Getenv("GITKRAKEN_CLIENT_ID"))
+	override(&s.AuthURL, cfg.AuthURL, os.Getenv("GITKRAKEN_AUTH_URL"))
+	override(&s.TokenURL, cfg.TokenURL, os.Getenv("GITKRAKEN_TOKEN_URL"))
+	override(&s.DeviceAuthURL, cfg.DeviceAuthURL, os.Getenv("GITKRAKEN_DEVICE_AUTH_URL"))
+	override(&s.RevokeURL, cfg.RevokeURL, os.Getenv("GITKRAKEN_REVOKE_URL"))
+	return s
+}
+
+// override sets *dst to the last non-empty value
+func override(dst *string, values ...string) {
+	for _, v := range values {
+		if v != "" {
+			*dst = v
+		}
+	}
+}
+
 // InitOAuth initializes the OAuth configuration
-func InitOAuth(clientID, clientSecret, redirectURL string) {
-	oauthConfig = &oauth2.Config{
-		ClientID:     clientID,
-		ClientSecret: clientSecret,
-		RedirectURL:  redirectURL,
-		Scopes:       []string{"read", "write"},
+func InitOAuth(s Settings) {
+	oauthConfig = s.oauth2Config()
+	revokeURL = s.RevokeURL
+}
+
+// currentConfig returns the OAuth configuration set by InitOAuth, loading
+// it from the settings in processes that did not log in
+func currentConfig() *oauth2.Config {
+	if oauthConfig == nil {
+		InitOAuth(LoadSettings())
+	}
+	return oauthConfig
+}
+
+func (s Settings) oauth2Config() *oauth2.Config {
+	return &oauth2.Config{
+		ClientID: s.ClientID,
+		Scopes:   s.Scopes,
 		Endpoint: oauth2.Endpoint{
-			AuthURL:  authURL,
-			TokenURL: tokenURL,
+			AuthURL:       s.AuthURL,
+			TokenURL:      s.TokenURL,
+			DeviceAuthURL: s.DeviceAuthURL,
+			// Public clients identify themselves with client_id in the body
+			AuthStyle: oauth2.AuthStyleInParams,
 		},
 	}
 }
@@ -50,108 +109,65 @@ func GenerateState() (string, error) {
 	return base64.URLEncoding.EncodeToString(b), nil
 }
 
-// StartAuthFlow starts the OAuth authentication flow
+// StartAuthFlow runs the authorization code flow with PKCE in the browser
+// and stores the resulting tokens
 func StartAuthFlow() error {
-	// Generate state
-	var err error
-	state, err = GenerateState()
+	token, err := authorize(context.Background(), currentConfig())
 	if err != nil {
-		return fmt.Errorf("failed to generate state: %w", err)
+		return err
 	}
 
-	// Generate auth URL
-	authURL := oauthConfig.AuthCodeURL(state, oauth2.AccessTypeOffline)
-
-	// Open browser
-	if err := openBrowser(authURL); err != nil {
-		fmt.Printf("Please open this URL in your browser:\n%s\n", authURL)
+	if err := saveToken(token); err != nil {
+		return fmt.Errorf("failed to save token: %w", err)
 	}
 
-	// Start local server to handle callback
-	code, err := startCallbackServer()
-	if err != nil {
-		return fmt.Errorf("failed to receive authorization code: %w", err)
-	}
+	fmt.Println("✓ Successfully authenticated!")
+	return nil
+}
 
-	// Exchange code for token
-	ctx := context.Background()
-	token, err := oauthConfig.Exchange(ctx, code)
+// authorize sends the user to the authorization page and exchanges the
+// returned code, proving possession of the PKCE verifier. The redirect goes
+// to a loopback server on a random free port.
+func authorize(ctx context.Context, conf *oauth2.Config) (*oauth2.Token, error) {
+	state, err := GenerateState()
 	if err != nil {
-		return fmt.Errorf("failed to exchange code for token: %w", err)
+		return nil, fmt.Errorf("failed to generate state: %w", err)
 	}
+	verifier := oauth2.GenerateVerifier()
 
-	// Save token to config
-	expiresAt := ""
-	if !token.Expiry.IsZero() {
-		expiresAt = token.Expiry.Format(time.RFC3339)
+	// Start local server before the browser can redirect to it
+	callback, err := newCallbackServer(state)
+	if err != nil {
+		return nil, err
 	}
+	defer callback.Close()
 
-	if err := config.SetAuthToken(token.AccessToken, token.RefreshToken, expiresAt); err != nil {
-		return fmt.Errorf("failed to save token: %w", err)
-	}
+	flowConf := *conf
+	flowConf.RedirectURL = callback.RedirectURL()
 
-	fmt.Println("✓ Successfully authenticated!")
-	return nil
-}
+	// Generate auth URL
+	authURL := flowConf.AuthCodeURL(state, oauth2.AccessTypeOffline, oauth2.S256ChallengeOption(verifier))
 
-// startCallbackServer starts a local HTTP server to receive the OAuth callback
-func startCallbackServer() (string, error) {
-	codeChan := make(chan string, 1)
-	errChan := make(chan error, 1)
-
-	server := &http.Server{
-		Addr: ":1314",
-		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Verify state
-			if r.URL.Query().Get("state") != state {
-				http.Error(w, "Invalid state parameter", http.StatusBadRequest)
-				errChan <- fmt.Errorf("invalid state parameter")
-				return
-			}
-
-			// Get authorization code
-			code := r.URL.Query().Get("code")
-			if code == "" {
-				http.Error(w, "Missing authorization code", http.StatusBadRequest)
-				errChan <- fmt.Errorf("missing authorization code")
-				return
-			}
-
-			// Send success response
-			w.WriteHeader(http.StatusOK)
-			w.Write([]byte("<html><body><h1>Success!</h1><p>You can close this window.</p></body></html>"))
-
-			codeChan <- code
-
-			// Shutdown server after a short delay
-			go func() {
-				time.Sleep(1 * time.Second)
-				server.Shutdown(context.Background())
-			}()
-		}),
-		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 10 * time.Second,
+	// Open browser
+	if err := openBrowser(authURL); err != nil {
+		fmt.Printf("Please open this URL in your browser:\n%s\n", authURL)
 	}
 
-	go func() {
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			errChan <- err
-		}
-	}()
+	code, err := callback.Wait(ctx, 5*time.Minute)
+	if err != nil {
+		return nil, fmt.Errorf("failed to receive authorization code: %w", err)
+	}
 
-	// Wait for code or error
-	select {
-	case code := <-codeChan:
-		return code, nil
-	case err := <-errChan:
-		return "", err
-	case <-time.After(5 * time.Minute):
-		return "", fmt.Errorf("authentication timeout")
+	// Exchange code for token
+	token, err := flowConf.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange code for token: %w", err)
 	}
+	return token, nil
 }
 
 // openBrowser opens the default browser with the given URL
-func openBrowser(url string) error {
+var openBrowser = func(url string) error {
 	var cmd *exec.Cmd
 	switch runtime.GOOS {
 	case "windows":
@@ -168,51 +184,3 @@ func openBrowser(url string) error {
 
 	return nil
 }
-
-// RefreshToken refreshes an expired access token
-func RefreshToken() error {
-	cfg := config.Get()
-	if cfg.Auth.RefreshToken == "" {
-		return fmt.Errorf("no refresh token available")
-	}
-
-	ctx := context.Background()
-	token := &oauth2.Token{
-		RefreshToken: cfg.Auth.RefreshToken,
-	}
-
-	tokenSource := oauthConfig.TokenSource(ctx, token)
-	newToken, err := tokenSource.Token()
-	if err != nil {
-		return fmt.Errorf("failed to refresh token: %w", err)
-	}
-
-	expiresAt := ""
-	if !newToken.Expiry.IsZero() {
-		expiresAt = newToken.Expiry.Format(time.RFC3339)
-	}
-
-	return config.SetAuthToken(newToken.AccessToken, newToken.RefreshToken, expiresAt)
-}
-
-// GetToken returns the current access token, refreshing if necessary
-func GetToken() (string, error) {
-	cfg := config.Get()
-	if cfg.Auth.Token == "" {
-		return "", fmt.Errorf("not authenticated. Run 'gk login'")
-	}
-
-	// Check if token is expired
-	if cfg.Auth.ExpiresAt != "" {
-		expiry, err := time.Parse(time.RFC3339, cfg.Auth.ExpiresAt)
-		if err == nil && time.Now().After(expiry.Add(-5*time.Minute)) {
-			// Token expired or expiring soon, refresh it
-			if err := RefreshToken(); err != nil {
-				return "", fmt.Errorf("failed to refresh token: %w", err)
-			}
-			cfg = config.Get()
-		}
-	}
-
-	return cfg.Auth.Token, nil
-}
@@ -0,0 +1,106 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// fakeOAuthServer is a minimal authorization server that only accepts
+// public clients using PKCE
+func fakeOAuthServer(t *testing.T) *httptest.Server {
+	var challenge string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" {
+			t.Errorf("expected S256 code challenge, got %q", r.URL.RawQuery)
+		}
+		challenge = q.Get("code_challenge")
+		redirect := q.Get("redirect_uri") + "?code=abc&state=" + url.QueryEscape(q.Get("state"))
+		http.Redirect(w, r, redirect, http.StatusFound)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if _, _, ok := r.BasicAuth(); ok || r.Form.Get("client_secret") != "" {
+			t.Errorf("public client must not send a client secret")
+		}
+		if r.Form.Get("client_id") != "test-client" || r.Form.Get("code") != "abc" {
+			t.Errorf("unexpected token request: %v", r.Form)
+		}
+		sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+		if base64.RawURLEncoding.EncodeToString(sum[:]) != challenge {
+			http.Error(w, `{"error": "invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  "access",
+			"refresh_token": "refresh",
+			"token_type":    "Bearer",
+			"expires_in":    3600,
+		})
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestAuthorizeWithPKCE(t *testing.T) {
+	server := fakeOAuthServer(t)
+	defer server.Close()
+
+	// Stand in for the user approving access in the browser
+	origOpenBrowser := openBrowser
+	defer func() { openBrowser = origOpenBrowser }()
+	openBrowser = func(authURL string) error {
+		go http.Get(authURL)
+		return nil
+	}
+
+	s := Settings{
+		ClientID: "test-client",
+		AuthURL:  server.URL + "/authorize",
+		TokenURL: server.URL + "/token",
+	}
+	token, err := authorize(context.Background(), s.oauth2Config())
+	if err != nil {
+		t.Fatalf("authorize failed: %v", err)
+	}
+	if token.AccessToken != "access" || token.RefreshToken != "refresh" {
+		t.Errorf("unexpected token: %+v", token)
+	}
+}
+
+func TestLoadSettingsEnvOverrides(t *testing.T) {
+	t.Setenv("GITKRAKEN_CLIENT_ID", "custom")
+	t.Setenv("GITKRAKEN_TOKEN_URL", "http://localhost:9999/token")
+
+	s := LoadSettings()
+	if s.ClientID != "custom" || s.TokenURL != "http://localhost:9999/token" {
+		t.Errorf("expected env overrides, got %+v", s)
+	}
+	if s.AuthURL != DefaultAuthURL {
+		t.Errorf("expected default auth URL, got %s", s.AuthURL)
+	}
+}
+
+func TestProviderDeviceLoginRequiresClientID(t *testing.T) {
+	t.Setenv("GK_GITHUB_CLIENT_ID", "")
+
+	_, err := ProviderDeviceLogin("github", io.Discard)
+	if err == nil || !strings.Contains(err.Error(), "GK_GITHUB_CLIENT_ID") {
+		t.Errorf("expected an error naming GK_GITHUB_CLIENT_ID, got %v", err)
+	}
+
+	t.Setenv("GK_GITHUB_CLIENT_ID", "Iv1.registered")
+	s, err := ProviderSettings("github")
+	if err != nil || s.ClientID != "Iv1.registered" {
+		t.Errorf("expected the client ID from the environment, got %+v, %v", s, err)
+	}
+}
@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gitkraken/gk-cli/internal/config"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/endpoints"
+)
+
+// clientIDEnv names the variable holding the client ID of the OAuth app
+// registered for each provider. gk has no registered app of its own, so the
+// device flow needs one set up by the user or their organization.
+var clientIDEnv = map[string]string{
+	"github": "GK_GITHUB_CLIENT_ID",
+	"gitlab": "GK_GITLAB_CLIENT_ID",
+}
+
+// ProviderSettings returns the OAuth settings for a hosting provider's
+// device flow, with the client ID from GK_GITHUB_CLIENT_ID or
+// GK_GITLAB_CLIENT_ID. The client ID is empty when the variable isn't set.
+func ProviderSettings(provider string) (Settings, error) {
+	var s Settings
+	provider = strings.ToLower(provider)
+	switch provider {
+	case "github":
+		s = Settings{
+			AuthURL:       endpoints.GitHub.AuthURL,
+			TokenURL:      endpoints.GitHub.TokenURL,
+			DeviceAuthURL: endpoints.GitHub.DeviceAuthURL,
+			Scopes:        []string{"repo", "read:org", "notifications"},
+		}
+	case "gitlab":
+		s = Settings{
+			AuthURL:       endpoints.GitLab.AuthURL,
+			TokenURL:      endpoints.GitLab.TokenURL,
+			DeviceAuthURL: endpoints.GitLab.DeviceAuthURL,
+			Scopes:        []string{"api"},
+		}
+	default:
+		return Settings{}, fmt.Errorf("OAuth is not supported for %s (supported: github, gitlab)", provider)
+	}
+	s.ClientID = os.Getenv(clientIDEnv[provider])
+	return s, nil
+}
+
+// ProviderDeviceLogin runs a provider's device flow and returns the provider
+// settings to store, including the refresh token and client ID needed to
+// refresh the access token later
+func ProviderDeviceLogin(provider string, out io.Writer) (config.ProviderConfig, error) {
+	s, err := ProviderSettings(provider)
+	if err != nil {
+		return config.ProviderConfig{}, err
+	}
+	if s.ClientID == "" {
+		env := clientIDEnv[strings.ToLower(provider)]
+		return config.ProviderConfig{}, fmt.Errorf("%s is not set: OAuth needs the client ID of an OAuth app registered on %s with the device flow enabled. Set it, or add a personal access token with --token", env, provider)
+	}
+
+	token, err := deviceAuthorize(context.Background(), s.oauth2Config(), out)
+	if err != nil {
+		return config.ProviderConfig{}, err
+	}
+	return providerTokenSettings(s.ClientID, token), nil
+}
+
+func providerTokenSettings(clientID string, token *oauth2.Token) config.ProviderConfig {
+	settings := config.ProviderConfig{
+		Auth:         config.ProviderAuthOAuth,
+		ClientID:     clientID,
+		Token:        token.AccessToken,
+		RefreshToken: token.RefreshToken,
+	}
+	if !token.Expiry.IsZero() {
+		settings.ExpiresAt = token.Expiry.Format(time.RFC3339)
+	}
+	return settings
+}
+
+// ProviderToken returns the access token for a provider account (github or
+// github@work), refreshing and saving it first if it was issued through
+// OAuth and expires soon. Refreshes hold the same lock as the GitKraken
+// token's: refresh tokens rotate, so a process that waited for the lock
+// uses the token the previous holder saved instead of refreshing again.
+func ProviderToken(key string) (string, error) {
+	settings, ok := config.Get().Providers[key]
+	if !ok {
+		return "", fmt.Errorf("%s is not configured", key)
+	}
+	if !providerExpiresSoon(settings) {
+		return settings.Token, nil
+	}
+
+	if lock, err := lockTokens(); err != nil {
+		return "", err
+	} else if lock != nil {
+		defer lock.Release()
+
+		if err := config.ReloadProviders(); err != nil {
+			return "", err
+		}
+		settings, ok = config.Get().Providers[key]
+		if !ok {
+			return "", fmt.Errorf("%s is not configured", key)
+		}
+		if !providerExpiresSoon(settings) {
+			return settings.Token, nil
+		}
+	}
+
+	provider, _, _ := strings.Cut(key, "@")
+	s, err := ProviderSettings(provider)
+	if err != nil {
+		return "", err
+	}
+	override(&s.ClientID, settings.ClientID)
+	if s.ClientID == "" {
+		return "", fmt.Errorf("failed to refresh %s token: no OAuth client ID is stored for it (add the provider with --oauth again)", key)
+	}
+
+	refreshToken := settings.RefreshToken
+	newToken, err := s.oauth2Config().TokenSource(refreshContext(), &oauth2.Token{RefreshToken: refreshToken}).Token()
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh %s token (add the provider with --oauth again): %w", key, err)
+	}
+	if newToken.RefreshToken == "" {
+		newToken.RefreshToken = refreshToken
+	}
+
+	if err := config.SetProvider(key, providerTokenSettings(s.ClientID, newToken)); err != nil {
+		return "", fmt.Errorf("failed to save refreshed %s token: %w", key, err)
+	}
+	return newToken.AccessToken, nil
+}
+
+// providerExpiresSoon reports whether a provider's OAuth token can be
+// refreshed and is about to expire
+func providerExpiresSoon(settings config.ProviderConfig) bool {
+	if settings.RefreshToken == "" || settings.ExpiresAt == "" {
+		return false
+	}
+	expiry, err := time.Parse(time.RFC3339, settings.ExpiresAt)
+	return err == nil && time.Now().After(expiry.Add(-expiryMargin))
+}
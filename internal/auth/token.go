@@ -0,0 +1,216 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gitkraken/gk-cli/internal/config"
+	"github.com/gitkraken/gk-cli/internal/filelock"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// expiryMargin is how long before expiry a token is refreshed
+	expiryMargin = 5 * time.Minute
+	// refreshLockTimeout is how long to wait for another process refreshing
+	refreshLockTimeout = time.Minute
+)
+
+// refreshTimeout bounds a token refresh request, which is made while other
+// gk processes wait for the token lock
+var refreshTimeout = 15 * time.Second
+
+// refreshContext returns the context for token refresh requests, with an
+// HTTP client that gives up on a token endpoint that doesn't answer
+func refreshContext() context.Context {
+	return context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Timeout: refreshTimeout})
+}
+
+// TokenSource returns a token source backed by the config file: it returns
+// the stored token, and refreshes and saves it when it is about to expire,
+// so refreshing works in every command and not only after 'gk login'
+func TokenSource() oauth2.TokenSource {
+	return &storedTokenSource{conf: currentConfig()}
+}
+
+type storedTokenSource struct {
+	conf *oauth2.Config
+}
+
+func (s *storedTokenSource) Token() (*oauth2.Token, error) {
+	token := StoredToken()
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("not authenticated. Run 'gk login'")
+	}
+	if !expiresSoon(token) {
+		return token, nil
+	}
+	return s.lockedRefresh(false)
+}
+
+// lockedRefresh refreshes the token while holding a lock shared by all gk
+// processes. Refresh tokens may be single use, so a process that waited
+// for the lock reuses the token the previous holder saved instead of
+// refreshing again, unless force is set.
+func (s *storedTokenSource) lockedRefresh(force bool) (*oauth2.Token, error) {
+	if lock, err := lockTokens(); err != nil {
+		return nil, err
+	} else if lock != nil {
+		defer lock.Release()
+
+		if err := config.ReloadAuth(); err != nil {
+			return nil, err
+		}
+	}
+
+	token := StoredToken()
+	if !force && token.AccessToken != "" && !expiresSoon(token) {
+		return token, nil
+	}
+	return s.refresh(token)
+}
+
+// lockTokens takes the lock that serializes token refreshes across gk
+// processes. It returns nil when there is no config file to guard.
+func lockTokens() (*filelock.Lock, error) {
+	path := config.Path()
+	if path == "" {
+		return nil, nil
+	}
+	return filelock.Acquire(filepath.Join(filepath.Dir(path), "token.lock"), refreshLockTimeout)
+}
+
+// refresh exchanges the refresh token for a new token and saves it
+func (s *storedTokenSource) refresh(token *oauth2.Token) (*oauth2.Token, error) {
+	if token.RefreshToken == "" {
+		return nil, fmt.Errorf("session expired. Run 'gk login'")
+	}
+
+	// Without an access token the refresh token is always used; a response
+	// without a new refresh token keeps the current one
+	stale := &oauth2.Token{RefreshToken: token.RefreshToken}
+	newToken, err := s.conf.TokenSource(refreshContext(), stale).Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh token: %w", err)
+	}
+	if err := saveToken(newToken); err != nil {
+		return nil, fmt.Errorf("failed to save token: %w", err)
+	}
+	return newToken, nil
+}
+
+// StoredToken returns the token saved in the config file
+func StoredToken() *oauth2.Token {
+	auth := config.Get().Auth
+	token := &oauth2.Token{
+		AccessToken:  auth.Token,
+		RefreshToken: auth.RefreshToken,
+		TokenType:    "Bearer",
+	}
+	if auth.ExpiresAt != "" {
+		if expiry, err := time.Parse(time.RFC3339, auth.ExpiresAt); err == nil {
+			token.Expiry = expiry
+		}
+	}
+	return token.WithExtra(map[string]interface{}{"scope": auth.Scope})
+}
+
+// StoredScopes returns the scopes granted to the stored token, if the
+// server reported them
+func StoredScopes() []string {
+	return strings.Fields(config.Get().Auth.Scope)
+}
+
+func expiresSoon(token *oauth2.Token) bool {
+	return !token.Expiry.IsZero() && time.Now().After(token.Expiry.Add(-expiryMargin))
+}
+
+// saveToken stores a token in the config file, keeping the previous scope
+// when a refresh response does not repeat it
+func saveToken(token *oauth2.Token) error {
+	auth := config.AuthConfig{
+		Token:        token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		Scope:        config.Get().Auth.Scope,
+	}
+	if !token.Expiry.IsZero() {
+		auth.ExpiresAt = token.Expiry.Format(time.RFC3339)
+	}
+	if scope, ok := token.Extra("scope").(string); ok && scope != "" {
+		auth.Scope = scope
+	}
+	return config.SetAuth(auth)
+}
+
+// RefreshToken refreshes the access token now, whether or not it expired
+func RefreshToken() error {
+	s := &storedTokenSource{conf: currentConfig()}
+	_, err := s.lockedRefresh(true)
+	return err
+}
+
+// GetToken returns the current access token, refreshing if necessary
+func GetToken() (string, error) {
+	token, err := TokenSource().Token()
+	if err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
+
+// Revoke asks the authorization server to revoke the stored refresh and
+// access tokens (RFC 7009). Tokens the server no longer knows about count
+// as revoked.
+func Revoke(ctx context.Context) error {
+	conf := currentConfig()
+	if revokeURL == "" {
+		return fmt.Errorf("no token revocation endpoint configured")
+	}
+
+	// Only stored tokens: one from GK_AUTH_TOKEN belongs to whoever set it
+	auth := config.Saved().Auth
+	for _, t := range []struct{ hint, value string }{
+		{"refresh_token", auth.RefreshToken},
+		{"access_token", auth.Token},
+	} {
+		if t.value == "" {
+			continue
+		}
+		if err := revokeToken(ctx, revokeURL, conf.ClientID, t.hint, t.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func revokeToken(ctx context.Context, endpoint, clientID, hint, token string) error {
+	form := url.Values{
+		"token":           {token},
+		"token_type_hint": {hint},
+		"client_id":       {clientID},
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create revocation request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to revoke %s: %w", strings.ReplaceAll(hint, "_", " "), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("failed to revoke %s (%d): %s", strings.ReplaceAll(hint, "_", " "), resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gitkraken/gk-cli/internal/config"
+)
+
+// TestMain points the config at a temporary home directory
+func TestMain(m *testing.M) {
+	home, err := os.MkdirTemp("", "gk-auth-test")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("HOME", home)
+	os.Setenv("GK_CREDENTIAL_STORE", "file")
+	if err := config.Init(); err != nil {
+		panic(err)
+	}
+
+	code := m.Run()
+	os.RemoveAll(home)
+	os.Exit(code)
+}
+
+func TestGetTokenRefreshesWithoutLogin(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("grant_type") != "refresh_token" || r.Form.Get("refresh_token") != "old-refresh" {
+			t.Errorf("unexpected refresh request: %v", r.Form)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token":  "new-access",
+			"refresh_token": "new-refresh",
+			"token_type":    "Bearer",
+			"expires_in":    3600,
+		})
+	}))
+	defer server.Close()
+
+	t.Setenv("GITKRAKEN_TOKEN_URL", server.URL)
+	// A new process that never ran InitOAuth
+	oauthConfig = nil
+	defer func() { oauthConfig = nil }()
+
+	expired := time.Now().Add(-time.Minute).Format(time.RFC3339)
+	if err := config.SetAuth(config.AuthConfig{Token: "old-access", RefreshToken: "old-refresh", ExpiresAt: expired, Scope: "read write"}); err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := GetToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "new-access" {
+		t.Errorf("expected refreshed token, got %q", token)
+	}
+	auth := config.Get().Auth
+	if auth.RefreshToken != "new-refresh" || auth.Scope != "read write" {
+		t.Errorf("refreshed token not saved: %+v", auth)
+	}
+}
+
+func TestRevoke(t *testing.T) {
+	var revoked []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		revoked = append(revoked, r.Form.Get("token_type_hint")+"="+r.Form.Get("token"))
+	}))
+	defer server.Close()
+
+	t.Setenv("GITKRAKEN_REVOKE_URL", server.URL)
+	// A token from the environment is never revoked
+	t.Setenv("GK_AUTH_TOKEN", "ci-access")
+	oauthConfig = nil
+	defer func() { oauthConfig = nil }()
+
+	if err := config.ClearAuth(); err != nil {
+		t.Fatal(err)
+	}
+	if err := Revoke(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(revoked) != 0 {
+		t.Fatalf("revoked a token from the environment: %v", revoked)
+	}
+
+	if err := config.SetAuth(config.AuthConfig{Token: "access", RefreshToken: "refresh"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Revoke(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(revoked) != 2 || revoked[0] != "refresh_token=refresh" || revoked[1] != "access_token=access" {
+		t.Errorf("unexpected revocations: %v", revoked)
+	}
+}
+
+func TestGetTokenReusesTokenRefreshedByAnotherProcess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("token was refreshed again")
+		http.Error(w, `{"error": "invalid_grant"}`, http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	t.Setenv("GITKRAKEN_TOKEN_URL", server.URL)
+	oauthConfig = nil
+	defer func() { oauthConfig = nil }()
+
+	// Another process saved a fresh token while this one still holds the
+	// expiring token it loaded at startup
+	fresh := time.Now().Add(time.Hour).Format(time.RFC3339)
+	if err := config.SetAuth(config.AuthConfig{Token: "fresh-access", RefreshToken: "fresh-refresh", ExpiresAt: fresh}); err != nil {
+		t.Fatal(err)
+	}
+	config.Get().Auth = config.AuthConfig{
+		Token:        "stale-access",
+		RefreshToken: "used-refresh",
+		ExpiresAt:    time.Now().Add(-time.Minute).Format(time.RFC3339),
+	}
+
+	token, err := GetToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "fresh-access" {
+		t.Errorf("expected the token saved by the other process, got %q", token)
+	}
+}
+
+func TestProviderTokenReusesTokenRefreshedByAnotherProcess(t *testing.T) {
+	// Another process refreshed the provider token while this one still
+	// holds the expiring token, whose refresh token was rotated away
+	fresh := config.ProviderConfig{
+		Auth:         config.ProviderAuthOAuth,
+		ClientID:     "client",
+		Token:        "fresh-access",
+		RefreshToken: "fresh-refresh",
+		ExpiresAt:    time.Now().Add(time.Hour).Format(time.RFC3339),
+	}
+	if err := config.SetProvider("github", fresh); err != nil {
+		t.Fatal(err)
+	}
+	defer config.RemoveProvider("github")
+
+	stale := fresh
+	stale.Token, stale.RefreshToken = "stale-access", "used-refresh"
+	stale.ExpiresAt = time.Now().Add(-time.Minute).Format(time.RFC3339)
+	config.Saved().Providers["github"] = stale
+
+	token, err := ProviderToken("github")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "fresh-access" {
+		t.Errorf("expected the token saved by the other process, got %q", token)
+	}
+}
+
+func TestRefreshGivesUpOnHungTokenEndpoint(t *testing.T) {
+	hung := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-hung
+	}))
+	defer server.Close()
+	defer close(hung)
+
+	t.Setenv("GITKRAKEN_TOKEN_URL", server.URL)
+	oauthConfig = nil
+	defer func() { oauthConfig = nil }()
+	refreshTimeout = 100 * time.Millisecond
+	defer func() { refreshTimeout = 15 * time.Second }()
+
+	expired := time.Now().Add(-time.Minute).Format(time.RFC3339)
+	if err := config.SetAuth(config.AuthConfig{Token: "old-access", RefreshToken: "old-refresh", ExpiresAt: expired}); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := GetToken()
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("expected the refresh to fail")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("refresh did not time out")
+	}
+}
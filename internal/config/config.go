@@ -1,69 +1,148 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/gitkraken/gk-cli/internal/filelock"
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
 )
 
 // Config represents the application configuration
 type Config struct {
-	Auth       AuthConfig       `mapstructure:"auth"`
-	Theme      string           `mapstructure:"theme"`
-	Providers  map[string]interface{} `mapstructure:"providers"`
-	Workspaces map[string]interface{} `mapstructure:"workspaces"`
+	Version   int                       `mapstructure:"version" yaml:"version" required:"true"`
+	Auth      AuthConfig                `mapstructure:"auth" yaml:"auth,omitempty"`
+	OAuth     OAuthConfig               `mapstructure:"oauth" yaml:"oauth,omitempty"`
+	Theme     string                    `mapstructure:"theme" yaml:"theme,omitempty"`
+	Providers map[string]ProviderConfig `mapstructure:"providers" yaml:"providers,omitempty"`
+	Routes    []RouteConfig             `mapstructure:"routes" yaml:"routes,omitempty"`
+
+	// Settings that a repository's .gk.yaml can override
+	Workspace string       `mapstructure:"workspace" yaml:"workspace,omitempty"`
+	Account   string       `mapstructure:"account" yaml:"account,omitempty"`
+	PR        PRConfig     `mapstructure:"pr" yaml:"pr,omitempty"`
+	Branch    BranchConfig `mapstructure:"branch" yaml:"branch,omitempty"`
 }
 
 // AuthConfig represents authentication configuration
 type AuthConfig struct {
-	Token        string `mapstructure:"token"`
-	RefreshToken string `mapstructure:"refresh_token"`
-	ExpiresAt    string `mapstructure:"expires_at"`
+	Token        string `mapstructure:"token" yaml:"token,omitempty"`
+	RefreshToken string `mapstructure:"refresh_token" yaml:"refresh_token,omitempty"`
+	ExpiresAt    string `mapstructure:"expires_at" yaml:"expires_at,omitempty"`
+	Scope        string `mapstructure:"scope" yaml:"scope,omitempty"`
+}
+
+// OAuthConfig overrides the OAuth client used by 'gk login'. Empty fields
+// fall back to the GitKraken defaults.
+type OAuthConfig struct {
+	ClientID      string `mapstructure:"client_id" yaml:"client_id,omitempty"`
+	AuthURL       string `mapstructure:"auth_url" yaml:"auth_url,omitempty"`
+	TokenURL      string `mapstructure:"token_url" yaml:"token_url,omitempty"`
+	DeviceAuthURL string `mapstructure:"device_auth_url" yaml:"device_auth_url,omitempty"`
+	RevokeURL     string `mapstructure:"revoke_url" yaml:"revoke_url,omitempty"`
+}
+
+// Provider authentication types
+const (
+	ProviderAuthToken   = ""        // personal access token or app password
+	ProviderAuthOAuth   = "oauth"   // OAuth token refreshed with RefreshToken
+	ProviderAuthDynamic = "dynamic" // token read from Source when used
+)
+
+// ProviderConfig holds the credentials of a provider account. Providers are
+// keyed by name (github) or name@account (github@work).
+type ProviderConfig struct {
+	Auth         string `mapstructure:"auth" yaml:"auth,omitempty"`
+	Token        string `mapstructure:"token" yaml:"token,omitempty"`
+	RefreshToken string `mapstructure:"refresh_token" yaml:"refresh_token,omitempty"`
+	ExpiresAt    string `mapstructure:"expires_at" yaml:"expires_at,omitempty"`
+	ClientID     string `mapstructure:"client_id" yaml:"client_id,omitempty"`
+	Source       string `mapstructure:"source" yaml:"source,omitempty"`
+	Username     string `mapstructure:"username" yaml:"username,omitempty"`
+	Password     string `mapstructure:"password" yaml:"password,omitempty"`
+}
+
+// PRConfig holds defaults for pull requests opened from a repository
+type PRConfig struct {
+	Base      string   `mapstructure:"base" yaml:"base,omitempty"`
+	Reviewers []string `mapstructure:"reviewers" yaml:"reviewers,omitempty"`
+}
+
+// BranchConfig holds the naming template for new branches, e.g.
+// {user}/{issue}-{title}
+type BranchConfig struct {
+	Template string `mapstructure:"template" yaml:"template,omitempty"`
+}
+
+// RouteConfig sends repositories matching a host/owner/repo glob to a named
+// provider account, e.g. github.com/acme/* to github@work
+type RouteConfig struct {
+	Match   string `mapstructure:"match" yaml:"match" required:"true"`
+	Account string `mapstructure:"account" yaml:"account" required:"true"`
 }
 
 var (
 	globalConfig *Config
+	effective    *Config
 	configPath   string
+	// configFile is the file given with --config, if any
+	configFile string
 )
 
+// SetFile makes Init read and write the given config file instead of the
+// profile's config.yaml
+func SetFile(path string) {
+	configFile = path
+}
+
 // Init initializes the configuration system
 func Init() error {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
-	}
-
-	// Default config paths
-	configDir := filepath.Join(home, ".config", "gk")
-	configPath = filepath.Join(configDir, "config.yaml")
+	viper.SetConfigType("yaml")
+	if configFile != "" {
+		configPath = configFile
+		viper.SetConfigFile(configFile)
+	} else {
+		// Each profile has its own config file
+		configDir, err := Dir()
+		if err != nil {
+			return err
+		}
+		configPath = filepath.Join(configDir, "config.yaml")
+		if err := os.MkdirAll(configDir, 0755); err != nil {
+			return fmt.Errorf("failed to create config directory: %w", err)
+		}
 
-	// Create config directory if it doesn't exist
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return fmt.Errorf("failed to create config directory: %w", err)
+		viper.SetConfigName("config")
+		viper.AddConfigPath(configDir)
 	}
 
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath(configDir)
-	viper.AddConfigPath(filepath.Join(home, ".gk"))
-
 	// Set defaults
 	viper.SetDefault("theme", "default")
-	viper.SetDefault("providers", make(map[string]interface{}))
-	viper.SetDefault("workspaces", make(map[string]interface{}))
 
-	// Read config file
+	// Read config file. A missing one is only written once something is
+	// saved, so gk can run from flags and environment variables alone.
 	if err := viper.ReadInConfig(); err != nil {
-		// Config file doesn't exist, create default
-		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-			if err := viper.SafeWriteConfigAs(configPath); err != nil {
-				return fmt.Errorf("failed to create config file: %w", err)
-			}
-		} else {
+		var notFound viper.ConfigFileNotFoundError
+		if !errors.As(err, &notFound) && !errors.Is(err, fs.ErrNotExist) {
 			return fmt.Errorf("failed to read config: %w", err)
 		}
+		viper.Set("version", CurrentVersion)
+	}
+
+	// Upgrade older config files to the current schema
+	raw := viper.AllSettings()
+	from, err := migrate(raw)
+	if err != nil {
+		return err
+	}
+	if err := viper.MergeConfigMap(raw); err != nil {
+		return fmt.Errorf("failed to apply config migrations: %w", err)
 	}
 
 	// Unmarshal config
@@ -71,56 +150,249 @@ func Init() error {
 	if err := viper.Unmarshal(globalConfig); err != nil {
 		return fmt.Errorf("failed to unmarshal config: %w", err)
 	}
+	if globalConfig.Providers == nil {
+		globalConfig.Providers = make(map[string]ProviderConfig)
+	}
+	effective = nil
+
+	// Apply the .gk.yaml of the repository we're in, if any
+	if err := loadRepoConfig(); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠ Ignoring repository config %v\n", err)
+	}
+
+	// Load secrets from the credential store before anything is saved:
+	// saving stores what the config holds as the secrets
+	plaintext, err := resolveSecrets(globalConfig)
+	if err != nil {
+		return err
+	}
+
+	// Write back upgraded files, moving any plaintext secrets out of them
+	if from < CurrentVersion || plaintext > 0 {
+		if err := saveAll(); err != nil {
+			return fmt.Errorf("failed to save migrated config: %w", err)
+		}
+	}
+	if from < CurrentVersion {
+		fmt.Fprintf(os.Stderr, "✓ Upgraded %s to config version %d\n", configPath, CurrentVersion)
+	}
+	if plaintext > 0 {
+		fmt.Fprintf(os.Stderr, "✓ Moved %d secret(s) from %s to %s\n", plaintext, configPath, SecretStoreName())
+	}
 
 	return nil
 }
 
-// Get returns the global configuration
+// Path returns the location of the config file
+func Path() string {
+	return configPath
+}
+
+// ReloadAuth re-reads the auth section from the config file, picking up
+// tokens another gk process saved since this one started
+func ReloadAuth() error {
+	fresh := &Config{}
+	if ok, err := reloadKey("auth", &fresh.Auth); !ok || err != nil {
+		return err
+	}
+	if _, err := resolveSecrets(fresh); err != nil {
+		return err
+	}
+	base().Auth = fresh.Auth
+	effective = nil
+	return nil
+}
+
+// ReloadProviders re-reads the providers section from the config file,
+// picking up provider tokens another gk process refreshed since this one
+// started
+func ReloadProviders() error {
+	fresh := &Config{}
+	if ok, err := reloadKey("providers", &fresh.Providers); !ok || err != nil {
+		return err
+	}
+	if fresh.Providers == nil {
+		fresh.Providers = make(map[string]ProviderConfig)
+	}
+	if _, err := resolveSecrets(fresh); err != nil {
+		return err
+	}
+	base().Providers = fresh.Providers
+	effective = nil
+	return nil
+}
+
+// reloadKey decodes one section of the config file as it is on disk into
+// out. It reports false if there is no config file yet.
+func reloadKey(key string, out interface{}) (bool, error) {
+	if configPath == "" {
+		return false, nil
+	}
+	v := viper.New()
+	v.SetConfigFile(configPath)
+	if err := v.ReadInConfig(); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to read config: %w", err)
+	}
+	if err := v.UnmarshalKey(key, out); err != nil {
+		return false, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	return true, nil
+}
+
+// Get returns the effective configuration: the global config file with
+// the repository's .gk.yaml applied on top. Changes to it are only saved
+// through the Update and Set functions.
 func Get() *Config {
+	if effective == nil {
+		effective = merge(base())
+	}
+	return effective
+}
+
+// Saved returns the configuration as stored in the config file, without
+// repository or environment overrides. It is read-only: settings are changed
+// through the Update and Set functions.
+func Saved() *Config {
+	return base()
+}
+
+// base returns the configuration stored in the global config file
+func base() *Config {
 	if globalConfig == nil {
-		// Return default config if not initialized
-		return &Config{
-			Theme:      "default",
-			Providers:  make(map[string]interface{}),
-			Workspaces: make(map[string]interface{}),
-		}
+		globalConfig = defaultConfig()
 	}
 	return globalConfig
 }
 
-// Save saves the current configuration to disk
-func Save() error {
-	if globalConfig == nil {
-		// Initialize if needed
-		globalConfig = &Config{
-			Theme:      "default",
-			Providers:  make(map[string]interface{}),
-			Workspaces: make(map[string]interface{}),
-		}
+func defaultConfig() *Config {
+	return &Config{
+		Version:   CurrentVersion,
+		Theme:     "default",
+		Providers: make(map[string]ProviderConfig),
 	}
+}
 
-	viper.Set("theme", globalConfig.Theme)
-	viper.Set("auth", globalConfig.Auth)
-	viper.Set("providers", globalConfig.Providers)
-	viper.Set("workspaces", globalConfig.Workspaces)
+// saveAll writes the whole configuration to disk, replacing what the file
+// holds. Init uses it to write back an upgraded file.
+func saveAll() error {
+	return writeConfig(func([]byte) (*Config, error) {
+		return base(), nil
+	})
+}
 
-	return viper.WriteConfigAs(configPath)
+// save applies change to the config file as it is on disk, not to the copy
+// this process loaded at startup, so settings other gk processes saved since
+// aren't lost. The file is locked from reading to writing, and the result
+// becomes the configuration in memory.
+func save(change func(cfg *Config) error) error {
+	return writeConfig(func(data []byte) (*Config, error) {
+		cfg, err := parseSaved(data)
+		if err != nil {
+			return nil, err
+		}
+		if err := change(cfg); err != nil {
+			return nil, err
+		}
+		return cfg, nil
+	})
 }
 
-// UpdateProviders updates providers in config
-func UpdateProviders(providers map[string]interface{}) error {
-	cfg := Get()
-	cfg.Providers = providers
+// writeConfig writes the configuration next returns for the current
+// contents of the config file, with its secrets moved to the credential
+// store
+func writeConfig(next func(data []byte) (*Config, error)) error {
+	var cfg *Config
+	err := filelock.Update(configPath, 0600, func(data []byte) ([]byte, error) {
+		var err error
+		if cfg, err = next(data); err != nil {
+			return nil, err
+		}
+		doc, err := storeSecrets(cfg)
+		if err != nil {
+			return nil, err
+		}
+		doc.Version = CurrentVersion
+
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(doc); err != nil {
+			return nil, fmt.Errorf("failed to encode config: %w", err)
+		}
+		return buf.Bytes(), nil
+	})
+	if err != nil {
+		return err
+	}
 	globalConfig = cfg
-	return Save()
+	effective = nil
+	return nil
+}
+
+// parseSaved decodes the config file as written by writeConfig and loads
+// its secrets
+func parseSaved(data []byte) (*Config, error) {
+	cfg := defaultConfig()
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", configPath, err)
+	}
+	if cfg.Providers == nil {
+		cfg.Providers = make(map[string]ProviderConfig)
+	}
+	if _, err := resolveSecrets(cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// SetProvider adds or replaces a provider account
+func SetProvider(key string, provider ProviderConfig) error {
+	return save(func(cfg *Config) error {
+		cfg.Providers[key] = provider
+		return nil
+	})
+}
+
+// RemoveProvider removes a provider account and the routes pointing at it,
+// and returns how many routes were removed
+func RemoveProvider(key string) (int, error) {
+	removed := 0
+	err := save(func(cfg *Config) error {
+		if _, ok := cfg.Providers[key]; !ok {
+			return fmt.Errorf("provider '%s' not found", key)
+		}
+		delete(cfg.Providers, key)
+
+		var routes []RouteConfig
+		for _, r := range cfg.Routes {
+			if r.Account != key {
+				routes = append(routes, r)
+			}
+		}
+		removed = len(cfg.Routes) - len(routes)
+		cfg.Routes = routes
+		return nil
+	})
+	return removed, err
+}
+
+// UpdateRoutes updates account routing rules in config
+func UpdateRoutes(routes []RouteConfig) error {
+	return save(func(cfg *Config) error {
+		cfg.Routes = routes
+		return nil
+	})
 }
 
 // SetTheme sets the theme in the configuration
 func SetTheme(theme string) error {
-	cfg := Get()
-	cfg.Theme = theme
-	globalConfig = cfg
-	return Save()
+	return save(func(cfg *Config) error {
+		cfg.Theme = theme
+		return nil
+	})
 }
 
 // GetTheme returns the current theme name
@@ -130,24 +402,40 @@ func GetTheme() string {
 
 // SetAuthToken sets the authentication token
 func SetAuthToken(token, refreshToken, expiresAt string) error {
-	cfg := Get()
-	cfg.Auth.Token = token
-	cfg.Auth.RefreshToken = refreshToken
-	cfg.Auth.ExpiresAt = expiresAt
-	globalConfig = cfg
-	return Save()
+	return save(func(cfg *Config) error {
+		cfg.Auth.Token = token
+		cfg.Auth.RefreshToken = refreshToken
+		cfg.Auth.ExpiresAt = expiresAt
+		return nil
+	})
+}
+
+// SetAuth replaces the stored authentication tokens
+func SetAuth(auth AuthConfig) error {
+	return save(func(cfg *Config) error {
+		cfg.Auth = auth
+		return nil
+	})
 }
 
 // ClearAuth clears authentication tokens
 func ClearAuth() error {
-	cfg := Get()
-	cfg.Auth = AuthConfig{}
-	globalConfig = cfg
-	return Save()
+	return save(func(cfg *Config) error {
+		cfg.Auth = AuthConfig{}
+		return nil
+	})
 }
 
-// IsAuthenticated checks if the user is authenticated
+// IsAuthenticated checks if the user has a token that is still valid or
+// can be refreshed
 func IsAuthenticated() bool {
 	cfg := Get()
-	return cfg.Auth.Token != ""
+	if cfg.Auth.Token == "" {
+		return false
+	}
+	if cfg.Auth.RefreshToken != "" || cfg.Auth.ExpiresAt == "" {
+		return true
+	}
+	expiry, err := time.Parse(time.RFC3339, cfg.Auth.ExpiresAt)
+	return err != nil || time.Now().Before(expiry)
 }
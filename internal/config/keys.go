@@ -0,0 +1,270 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Setting is a config value addressed by its dotted key
+type Setting struct {
+	Key    string
+	Value  string
+	Origin string // e.g. file:/home/me/.config/gk/config.yaml or env:GK_THEME
+}
+
+// secretMask replaces secret values in listings
+const secretMask = "********"
+
+// GetValue returns the value of a dotted key such as theme or
+// providers.github.username
+func GetValue(key string) (string, error) {
+	v, err := lookup(reflect.ValueOf(Get()).Elem(), splitKey(key), key)
+	if err != nil {
+		return "", err
+	}
+	if v.Kind() == reflect.Struct || v.Kind() == reflect.Map || (v.Kind() == reflect.Slice && !isStringList(v)) {
+		return "", fmt.Errorf("%s is a section; use 'gk config list' to see its keys", key)
+	}
+	return formatValue(v), nil
+}
+
+// SetValue parses value according to the type of a dotted key, checks the
+// result against the schema and saves it
+func SetValue(key, value string) error {
+	return update(key, func(cfg *Config) error {
+		return assign(reflect.ValueOf(cfg).Elem(), splitKey(key), key, &value)
+	})
+}
+
+// UnsetValue removes a dotted key, or a whole entry such as
+// providers.github, and saves the result
+func UnsetValue(key string) error {
+	return update(key, func(cfg *Config) error {
+		return assign(reflect.ValueOf(cfg).Elem(), splitKey(key), key, nil)
+	})
+}
+
+// List returns every setting that has a value, sorted by key, with secrets
+// masked
+func List() []Setting {
+	var settings []Setting
+	flatten(reflect.ValueOf(Get()).Elem(), "", &settings)
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Key < settings[j].Key })
+	for i := range settings {
+		settings[i].Origin = Origin(settings[i].Key)
+	}
+	return settings
+}
+
+// update applies a change to the saved config and saves it unless the
+// change introduces schema problems
+func update(key string, change func(*Config) error) error {
+	return save(func(cfg *Config) error {
+		before, err := issuesFor(cfg)
+		if err != nil {
+			return err
+		}
+		if err := change(cfg); err != nil {
+			return err
+		}
+
+		after, err := issuesFor(cfg)
+		if err != nil {
+			return err
+		}
+		known := make(map[string]bool, len(before))
+		for _, issue := range before {
+			known[issue.Path+issue.Message] = true
+		}
+		for _, issue := range after {
+			if !known[issue.Path+issue.Message] {
+				return fmt.Errorf("invalid value for %s: %s: %s", key, issue.Path, issue.Message)
+			}
+		}
+		return nil
+	})
+}
+
+// issuesFor validates an in-memory config
+func issuesFor(cfg *Config) ([]Issue, error) {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	return Validate(data)
+}
+
+func splitKey(key string) []string {
+	return strings.Split(strings.Trim(key, "."), ".")
+}
+
+// lookup resolves the remaining key parts below v
+func lookup(v reflect.Value, parts []string, key string) (reflect.Value, error) {
+	if len(parts) == 0 {
+		return v, nil
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		field, ok := fieldByKey(v.Type(), parts[0])
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("unknown key: %s", key)
+		}
+		return lookup(v.FieldByIndex(field.Index), parts[1:], key)
+	case reflect.Map:
+		elem := v.MapIndex(reflect.ValueOf(parts[0]))
+		if !elem.IsValid() {
+			return reflect.Value{}, fmt.Errorf("%s is not set", key)
+		}
+		return lookup(elem, parts[1:], key)
+	case reflect.Slice:
+		i, err := strconv.Atoi(parts[0])
+		if err != nil || i < 0 || i >= v.Len() {
+			return reflect.Value{}, fmt.Errorf("%s is not set", key)
+		}
+		return lookup(v.Index(i), parts[1:], key)
+	}
+	return reflect.Value{}, fmt.Errorf("unknown key: %s", key)
+}
+
+// assign sets the value at the remaining key parts below v, or clears it
+// when value is nil. Map entries are created as needed; since map elements
+// aren't addressable they are copied, changed and stored back.
+func assign(v reflect.Value, parts []string, key string, value *string) error {
+	if len(parts) == 0 {
+		if value == nil {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		return parseInto(v, *value, key)
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		field, ok := fieldByKey(v.Type(), parts[0])
+		if !ok {
+			return fmt.Errorf("unknown key: %s", key)
+		}
+		return assign(v.FieldByIndex(field.Index), parts[1:], key, value)
+	case reflect.Map:
+		name := reflect.ValueOf(parts[0])
+		if value == nil && len(parts) == 1 {
+			v.SetMapIndex(name, reflect.Value{})
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if existing := v.MapIndex(name); existing.IsValid() {
+			elem.Set(existing)
+		}
+		if err := assign(elem, parts[1:], key, value); err != nil {
+			return err
+		}
+		v.SetMapIndex(name, elem)
+		return nil
+	case reflect.Slice:
+		return fmt.Errorf("%s is a list and can't be changed with gk config set; use 'gk config edit'", splitKey(key)[0])
+	}
+	return fmt.Errorf("unknown key: %s", key)
+}
+
+// parseInto parses a command-line value into a leaf of the config
+func parseInto(v reflect.Value, value, key string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s must be an integer", key)
+		}
+		v.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s must be true or false", key)
+		}
+		v.SetBool(b)
+	case reflect.Slice:
+		if !isStringList(v) {
+			return fmt.Errorf("%s is a list and can't be changed with gk config set; use 'gk config edit'", key)
+		}
+		// Comma-separated, e.g. pr.reviewers=alice,bob
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("%s is a section; set one of its keys instead", key)
+	}
+	return nil
+}
+
+// flatten collects the non-empty leaves below v as dotted keys
+func flatten(v reflect.Value, prefix string, out *[]Setting) {
+	switch v.Kind() {
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			flatten(v.Field(i), joinPath(prefix, yamlName(v.Type().Field(i))), out)
+		}
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			flatten(v.MapIndex(k), joinPath(prefix, k.String()), out)
+		}
+	case reflect.Slice:
+		if isStringList(v) {
+			if v.Len() > 0 {
+				*out = append(*out, Setting{Key: prefix, Value: formatValue(v)})
+			}
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			flatten(v.Index(i), joinPath(prefix, strconv.Itoa(i)), out)
+		}
+	default:
+		if v.IsZero() {
+			return
+		}
+		value := formatValue(v)
+		if isSecretKey(prefix) {
+			value = secretMask
+		}
+		*out = append(*out, Setting{Key: prefix, Value: value})
+	}
+}
+
+func formatValue(v reflect.Value) string {
+	if isStringList(v) {
+		return strings.Join(v.Interface().([]string), ",")
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// isStringList reports whether v is a list of strings such as pr.reviewers,
+// which is read and written as a comma-separated value
+func isStringList(v reflect.Value) bool {
+	return v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String
+}
+
+// isSecretKey reports whether a dotted key holds a secret
+func isSecretKey(key string) bool {
+	parts := splitKey(key)
+	switch {
+	case len(parts) == 2 && parts[0] == "auth":
+		return parts[1] == "token" || parts[1] == "refresh_token"
+	case len(parts) == 3 && parts[0] == "providers":
+		var p ProviderConfig
+		_, ok := providerSecrets(&p)[parts[2]]
+		return ok
+	}
+	return false
+}
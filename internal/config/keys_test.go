@@ -0,0 +1,97 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gitkraken/gk-cli/internal/credentials"
+)
+
+func TestSetGetUnsetValue(t *testing.T) {
+	secretStore = credentials.NewFileStore(t.TempDir())
+	storedSecrets = make(map[string]string)
+	configPath = filepath.Join(t.TempDir(), "config.yaml")
+	globalConfig = defaultConfig()
+	defer func() {
+		secretStore = nil
+		configPath = ""
+		globalConfig = nil
+	}()
+
+	if err := SetValue("providers.bitbucket.username", "alice"); err == nil {
+		t.Error("expected a bitbucket provider without a password to be rejected")
+	}
+	if err := SetValue("providers.github.token", "ghp_secret"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetValue("theme", "dark"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetValue("version", "one"); err == nil {
+		t.Error("expected a non-integer version to be rejected")
+	}
+	if err := SetValue("providers.github.tokn", "x"); err == nil {
+		t.Error("expected an unknown key to be rejected")
+	}
+
+	if value, err := GetValue("providers.github.token"); err != nil || value != "ghp_secret" {
+		t.Errorf("GetValue = %q, %v", value, err)
+	}
+	for _, s := range List() {
+		if s.Key == "providers.github.token" && s.Value != secretMask {
+			t.Errorf("expected token to be masked in list, got %q", s.Value)
+		}
+	}
+
+	if err := UnsetValue("providers.github"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GetValue("providers.github.token"); err == nil {
+		t.Error("expected providers.github to be removed")
+	}
+	if value, _ := GetValue("theme"); value != "dark" {
+		t.Errorf("theme = %q, want dark", value)
+	}
+}
+
+func TestSaveKeepsChangesFromOtherProcesses(t *testing.T) {
+	secretStore = credentials.NewFileStore(t.TempDir())
+	storedSecrets = make(map[string]string)
+	configPath = filepath.Join(t.TempDir(), "config.yaml")
+	globalConfig = defaultConfig()
+	defer func() {
+		secretStore = nil
+		configPath = ""
+		globalConfig = nil
+	}()
+
+	// Another process adds a provider while this one holds the config it
+	// loaded at startup
+	if err := SetProvider("gitlab", ProviderConfig{Token: "glpat"}); err != nil {
+		t.Fatal(err)
+	}
+	globalConfig = defaultConfig()
+
+	if err := SetValue("workspace", "app"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetTheme("dark"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved, err := parseSaved(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Providers["gitlab"].Token != "glpat" || saved.Workspace != "app" || saved.Theme != "dark" {
+		t.Errorf("unexpected saved config: %+v", saved)
+	}
+	if Saved().Providers["gitlab"].Token != "glpat" {
+		t.Errorf("expected the saved provider in memory, got %+v", Saved().Providers)
+	}
+}
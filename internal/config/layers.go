@@ -0,0 +1,269 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RepoConfigName is the file a repository can commit to override settings
+// for everyone working in it
+const RepoConfigName = ".gk.yaml"
+
+// RepoConfig is the schema of a repository's .gk.yaml. Its keys mirror the
+// keys of the same name in the global config.
+type RepoConfig struct {
+	Workspace string       `mapstructure:"workspace" yaml:"workspace,omitempty"`
+	Account   string       `mapstructure:"account" yaml:"account,omitempty"`
+	PR        PRConfig     `mapstructure:"pr" yaml:"pr,omitempty"`
+	Branch    BranchConfig `mapstructure:"branch" yaml:"branch,omitempty"`
+}
+
+var (
+	repoConfig     *RepoConfig
+	repoConfigPath string
+
+	// origins records where overridden keys came from, e.g.
+	// "file:/src/app/.gk.yaml" or "env:GK_WORKSPACE"
+	origins map[string]string
+)
+
+// RepoConfigPath returns the .gk.yaml that applies to the current
+// directory, or "" if there is none
+func RepoConfigPath() string {
+	return repoConfigPath
+}
+
+// findRepoConfig walks up from dir looking for a .gk.yaml
+func findRepoConfig(dir string) string {
+	for {
+		path := filepath.Join(dir, RepoConfigName)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// loadRepoConfig reads the .gk.yaml above the working directory, if any
+func loadRepoConfig() error {
+	repoConfig, repoConfigPath = nil, ""
+	effective = nil
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil
+	}
+	path := findRepoConfig(cwd)
+	if path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	issues, err := ValidateRepo(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if len(issues) > 0 {
+		return fmt.Errorf("%s: %s (run 'gk config validate %s')", path, issues[0], path)
+	}
+
+	cfg := &RepoConfig{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	repoConfig, repoConfigPath = cfg, path
+	return nil
+}
+
+// merge layers the repository's .gk.yaml and GK_ environment variables over
+// the global config. Flags are applied on top by the commands themselves.
+func merge(global *Config) *Config {
+	merged := *global
+	origins = make(map[string]string)
+
+	if repoConfig != nil {
+		overlay(reflect.ValueOf(&merged).Elem(), reflect.ValueOf(repoConfig).Elem(), "", "file:"+repoConfigPath)
+	}
+	applyEnv(reflect.ValueOf(&merged).Elem(), "")
+	envCredentials(&merged)
+	return &merged
+}
+
+// overlay copies the non-empty fields of src onto the fields of dst with
+// the same yaml key
+func overlay(dst, src reflect.Value, prefix, origin string) {
+	for i := 0; i < src.NumField(); i++ {
+		name := yamlName(src.Type().Field(i))
+		field, ok := fieldByKey(dst.Type(), name)
+		if !ok {
+			continue
+		}
+		key := joinPath(prefix, name)
+		value, target := src.Field(i), dst.FieldByIndex(field.Index)
+		if value.Kind() == reflect.Struct {
+			overlay(target, value, key, origin)
+			continue
+		}
+		if !value.IsZero() {
+			target.Set(value)
+			origins[key] = origin
+		}
+	}
+}
+
+// applyEnv sets the leaves below v that are found in the environment as GK_
+// variables, e.g. pr.base from GK_PR_BASE
+func applyEnv(v reflect.Value, prefix string) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		key := joinPath(prefix, yamlName(field))
+		target := v.Field(i)
+
+		switch {
+		case key == "version":
+			// The schema version describes the file, not the settings
+		case target.Kind() == reflect.Struct:
+			applyEnv(target, key)
+		case target.Kind() == reflect.Map:
+			applyMapEnv(target, key)
+		case target.Kind() == reflect.Slice && !isStringList(target):
+			// Lists of sections such as routes can't be set from the environment
+		default:
+			setFromEnv(target, key, EnvName(key))
+		}
+	}
+}
+
+// applyMapEnv sets fields of map entries from the environment, e.g.
+// providers.github.token from GK_PROVIDERS_GITHUB_TOKEN. Since an entry
+// name can't be told apart from the field names that follow it, the known
+// field names are matched from the end; an underscore left in the entry
+// name separates a provider from its account, so GK_PROVIDERS_GITHUB_WORK_TOKEN
+// sets providers.github@work.token. The map is copied before it is changed
+// so the saved config is left alone.
+func applyMapEnv(m reflect.Value, key string) {
+	prefix := EnvName(key) + "_"
+	elemType := m.Type().Elem()
+
+	// Longest field names first, so REFRESH_TOKEN isn't taken for TOKEN
+	var fields []string
+	for i := 0; i < elemType.NumField(); i++ {
+		fields = append(fields, yamlName(elemType.Field(i)))
+	}
+	sort.Slice(fields, func(i, j int) bool { return len(fields[i]) > len(fields[j]) })
+
+	copied := false
+	for _, env := range os.Environ() {
+		name, _, _ := strings.Cut(env, "=")
+		rest, ok := strings.CutPrefix(name, prefix)
+		if !ok {
+			continue
+		}
+		for _, field := range fields {
+			entry, ok := strings.CutSuffix(rest, "_"+strings.ToUpper(field))
+			if !ok || entry == "" {
+				continue
+			}
+			entry = strings.ToLower(strings.Replace(entry, "_", "@", 1))
+
+			if !copied {
+				clone := reflect.MakeMapWithSize(m.Type(), m.Len())
+				for iter := m.MapRange(); iter.Next(); {
+					clone.SetMapIndex(iter.Key(), iter.Value())
+				}
+				m.Set(clone)
+				copied = true
+			}
+			elem := reflect.New(elemType).Elem()
+			if existing := m.MapIndex(reflect.ValueOf(entry)); existing.IsValid() {
+				elem.Set(existing)
+			}
+			f, _ := fieldByKey(elemType, field)
+			if setFromEnv(elem.FieldByIndex(f.Index), joinPath(key, entry+"."+field), name) {
+				m.SetMapIndex(reflect.ValueOf(entry), elem)
+			}
+			break
+		}
+	}
+}
+
+// setFromEnv parses an environment variable into a leaf of the config and
+// reports whether it was set
+func setFromEnv(target reflect.Value, key, env string) bool {
+	value, ok := os.LookupEnv(env)
+	if !ok || value == "" {
+		return false
+	}
+	if err := parseInto(target, value, key); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠ Ignoring %s: %v\n", env, err)
+		return false
+	}
+	origins[key] = "env:" + env
+	return true
+}
+
+// envCredentials drops the stored refresh state of tokens replaced from the
+// environment, so gk uses them as they are instead of refreshing the stored
+// ones
+func envCredentials(cfg *Config) {
+	fromEnv := func(key string) bool {
+		return strings.HasPrefix(origins[key], "env:")
+	}
+
+	if fromEnv("auth.token") {
+		if !fromEnv("auth.refresh_token") {
+			cfg.Auth.RefreshToken = ""
+		}
+		if !fromEnv("auth.expires_at") {
+			cfg.Auth.ExpiresAt = ""
+		}
+	}
+	for name, p := range cfg.Providers {
+		path := "providers." + name
+		if !fromEnv(path + ".token") {
+			continue
+		}
+		if !fromEnv(path + ".auth") {
+			p.Auth = ProviderAuthToken
+		}
+		if !fromEnv(path + ".refresh_token") {
+			p.RefreshToken, p.ExpiresAt = "", ""
+		}
+		cfg.Providers[name] = p
+	}
+}
+
+// EnvName returns the environment variable that overrides a dotted key
+func EnvName(key string) string {
+	return "GK_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// Origin returns where the value of a dotted key came from, e.g.
+// file:/home/me/.config/gk/config.yaml or env:GK_THEME
+func Origin(key string) string {
+	Get()
+	for k := key; k != ""; {
+		if origin, ok := origins[k]; ok {
+			return origin
+		}
+		i := strings.LastIndex(k, ".")
+		if i < 0 {
+			break
+		}
+		k = k[:i]
+	}
+	return "file:" + configPath
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRepoConfigLayers(t *testing.T) {
+	root := t.TempDir()
+	sub := filepath.Join(root, "src", "pkg")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatal(err)
+	}
+	repoFile := filepath.Join(root, RepoConfigName)
+	data := "workspace: app\npr:\n  base: develop\n  reviewers: [alice, bob]\n"
+	if err := os.WriteFile(repoFile, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got := findRepoConfig(sub); got != repoFile {
+		t.Fatalf("findRepoConfig = %q, want %q", got, repoFile)
+	}
+
+	wd, _ := os.Getwd()
+	if err := os.Chdir(sub); err != nil {
+		t.Fatal(err)
+	}
+	configPath = filepath.Join(root, "config.yaml")
+	globalConfig = defaultConfig()
+	globalConfig.Workspace = "global"
+	globalConfig.Branch.Template = "{user}/{title}"
+	t.Setenv("GK_PR_BASE", "main")
+	defer func() {
+		os.Chdir(wd)
+		repoConfig, repoConfigPath = nil, ""
+		configPath = ""
+		globalConfig, effective = nil, nil
+	}()
+
+	if err := loadRepoConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := Get()
+	if cfg.Workspace != "app" || cfg.PR.Base != "main" || len(cfg.PR.Reviewers) != 2 || cfg.Branch.Template != "{user}/{title}" {
+		t.Errorf("unexpected merged config: %+v", cfg)
+	}
+	if base().Workspace != "global" {
+		t.Error("the repository layer must not change the global config")
+	}
+
+	tests := map[string]string{
+		"workspace":       "file:" + repoFile,
+		"pr.base":         "env:GK_PR_BASE",
+		"pr.reviewers":    "file:" + repoFile,
+		"branch.template": "file:" + configPath,
+	}
+	for key, want := range tests {
+		if got := Origin(key); got != want {
+			t.Errorf("Origin(%s) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestEnvOverrides(t *testing.T) {
+	configPath = filepath.Join(t.TempDir(), "config.yaml")
+	globalConfig = defaultConfig()
+	globalConfig.Auth = AuthConfig{Token: "stored", RefreshToken: "refresh", ExpiresAt: "2020-01-01T00:00:00Z"}
+	globalConfig.Providers["github"] = ProviderConfig{Token: "stored"}
+	defer func() {
+		configPath = ""
+		globalConfig, effective = nil, nil
+	}()
+
+	t.Setenv("GK_THEME", "dark")
+	t.Setenv("GK_AUTH_TOKEN", "ci")
+	t.Setenv("GK_PROVIDERS_GITHUB_WORK_REFRESH_TOKEN", "r")
+	t.Setenv("GK_PROVIDERS_GITLAB_TOKEN", "glpat")
+	t.Setenv("GK_VERSION", "7")
+	effective = nil
+
+	cfg := Get()
+	if cfg.Theme != "dark" || cfg.Version != CurrentVersion {
+		t.Errorf("theme = %q, version = %d", cfg.Theme, cfg.Version)
+	}
+	if cfg.Auth.Token != "ci" || cfg.Auth.RefreshToken != "" || cfg.Auth.ExpiresAt != "" {
+		t.Errorf("expected the environment token to replace the stored session, got %+v", cfg.Auth)
+	}
+	if cfg.Providers["gitlab"].Token != "glpat" || cfg.Providers["github@work"].RefreshToken != "r" || cfg.Providers["github"].Token != "stored" {
+		t.Errorf("unexpected providers: %+v", cfg.Providers)
+	}
+	if _, ok := base().Providers["gitlab"]; ok || base().Theme != "default" {
+		t.Error("environment overrides must not change the saved config")
+	}
+	if got := Origin("providers.github@work.refresh_token"); got != "env:GK_PROVIDERS_GITHUB_WORK_REFRESH_TOKEN" {
+		t.Errorf("Origin = %q", got)
+	}
+}
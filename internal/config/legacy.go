@@ -0,0 +1,135 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gitkraken/gk-cli/pkg/utils"
+)
+
+// legacyMarker is left in a legacy directory that still holds files after
+// migrating, so the notice is only shown once
+const legacyMarker = "MIGRATED"
+
+// MigrateLegacyPaths moves the files older versions of gk kept in ~/.gk to
+// the XDG config directory. It prints a notice when it moves anything.
+func MigrateLegacyPaths() error {
+	configDir, err := utils.GetConfigDir()
+	if err != nil {
+		return fmt.Errorf("failed to get config directory: %w", err)
+	}
+
+	legacyDir, err := utils.GetLegacyDir()
+	if err != nil {
+		return nil
+	}
+	entries, err := os.ReadDir(legacyDir)
+	if err != nil {
+		return nil
+	}
+	if _, err := os.Stat(filepath.Join(legacyDir, legacyMarker)); err == nil {
+		return nil
+	}
+
+	m := &migrator{}
+	for _, entry := range entries {
+		name := entry.Name()
+		m.move(filepath.Join(legacyDir, name), filepath.Join(configDir, name))
+	}
+	if m.moved == 0 && len(m.skipped) == 0 {
+		os.Remove(legacyDir)
+		return nil
+	}
+
+	fmt.Fprintf(os.Stderr, "✓ Moved your gk files from %s to %s\n", legacyDir, configDir)
+
+	if len(m.skipped) == 0 {
+		os.Remove(legacyDir)
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "⚠ Left %d file(s) in %s that already exist in the new location:\n", len(m.skipped), legacyDir)
+	for _, path := range m.skipped {
+		fmt.Fprintf(os.Stderr, "    %s\n", path)
+	}
+	note := "These files were not moved by gk because newer copies already exist in " + configDir + ".\n"
+	if err := os.WriteFile(filepath.Join(legacyDir, legacyMarker), []byte(note), 0644); err != nil {
+		return fmt.Errorf("failed to mark %s as migrated: %w", legacyDir, err)
+	}
+	return nil
+}
+
+// migrator moves files and directories, merging directories that already
+// exist and never overwriting files
+type migrator struct {
+	moved   int
+	skipped []string
+}
+
+func (m *migrator) move(src, dst string) {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return
+	}
+	// Lock files only matter to running processes
+	if !info.IsDir() && strings.HasSuffix(src, ".lock") {
+		os.Remove(src)
+		return
+	}
+
+	if existing, err := os.Stat(dst); err == nil {
+		if !info.IsDir() || !existing.IsDir() {
+			m.skipped = append(m.skipped, src)
+			return
+		}
+		entries, err := os.ReadDir(src)
+		if err != nil {
+			m.skipped = append(m.skipped, src)
+			return
+		}
+		for _, entry := range entries {
+			m.move(filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name()))
+		}
+		os.Remove(src)
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		m.skipped = append(m.skipped, src)
+		return
+	}
+	if err := os.Rename(src, dst); err != nil {
+		// The XDG directories may be on another file system
+		if info.IsDir() || copyFile(src, dst, info.Mode().Perm()) != nil {
+			m.skipped = append(m.skipped, src)
+			return
+		}
+		os.Remove(src)
+	}
+	m.moved++
+}
+
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return err
+	}
+	return nil
+}
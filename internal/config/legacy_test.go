@@ -0,0 +1,56 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMigrateLegacyPaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	write := func(path, data string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	legacy := filepath.Join(home, ".gk")
+	config := filepath.Join(home, ".config", "gk")
+	write(filepath.Join(legacy, "config.yaml"), "theme: old")
+	write(filepath.Join(legacy, "themes", "night.json"), "{}")
+	write(filepath.Join(legacy, "workspaces", "app.json"), "{}")
+	write(filepath.Join(config, "workspaces", "app.json"), "{\"name\":\"app\"}")
+
+	if err := MigrateLegacyPaths(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{
+		filepath.Join(config, "config.yaml"),
+		filepath.Join(config, "themes", "night.json"),
+		filepath.Join(legacy, "workspaces", "app.json"),
+		filepath.Join(legacy, legacyMarker),
+	} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("expected %s to exist: %v", path, err)
+		}
+	}
+	if data, _ := os.ReadFile(filepath.Join(config, "workspaces", "app.json")); string(data) != "{\"name\":\"app\"}" {
+		t.Errorf("existing workspace was overwritten: %s", data)
+	}
+
+	// The remaining file is left alone from now on
+	write(filepath.Join(legacy, "later.yaml"), "")
+	if err := MigrateLegacyPaths(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(config, "later.yaml")); err == nil {
+		t.Error("expected no further migration after the first one")
+	}
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gitkraken/gk-cli/internal/filelock"
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentVersion is the config schema version this gk reads and writes
+const CurrentVersion = 1
+
+// migration upgrades a raw config document from one version to the next
+type migration struct {
+	description string
+	apply       func(raw map[string]interface{}) error
+}
+
+// migrations upgrade the config one version at a time: migrations[i] turns
+// version i into version i+1. Config files without a version are version 0.
+var migrations = []migration{
+	{
+		description: "move the unused workspaces section out of the config; workspaces are stored in the workspaces directory",
+		apply: func(raw map[string]interface{}) error {
+			if section, ok := raw["workspaces"].(map[string]interface{}); ok && len(section) > 0 {
+				if err := setAside("workspaces", section); err != nil {
+					return err
+				}
+			}
+			delete(raw, "workspaces")
+			return nil
+		},
+	},
+}
+
+// setAside saves a section that is no longer read to a file next to the
+// config, so removing it from the config doesn't lose anything
+func setAside(key string, section map[string]interface{}) error {
+	if configPath == "" {
+		return fmt.Errorf("no config file to keep the %s section next to", key)
+	}
+	path := filepath.Join(filepath.Dir(configPath), "config."+key+".yaml")
+	data, err := yaml.Marshal(map[string]interface{}{key: section})
+	if err != nil {
+		return fmt.Errorf("failed to encode the %s section: %w", key, err)
+	}
+	if err := filelock.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to save the %s section: %w", key, err)
+	}
+
+	names := make([]string, 0, len(section))
+	for name := range section {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintf(os.Stderr, "⚠ gk no longer reads the %s section of %s; moved it to %s (%s)\n",
+		key, configPath, path, strings.Join(names, ", "))
+	return nil
+}
+
+// configVersion returns the schema version of a raw config document
+func configVersion(raw map[string]interface{}) (int, error) {
+	switch v := raw["version"].(type) {
+	case nil:
+		return 0, nil
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case float64:
+		return int(v), nil
+	default:
+		return 0, fmt.Errorf("invalid config version %v", v)
+	}
+}
+
+// migrate upgrades a raw config document to CurrentVersion in place and
+// returns the version it started from
+func migrate(raw map[string]interface{}) (int, error) {
+	from, err := configVersion(raw)
+	if err != nil {
+		return 0, err
+	}
+	if from > CurrentVersion {
+		return from, fmt.Errorf("config version %d is newer than this gk supports (%d); please upgrade gk", from, CurrentVersion)
+	}
+
+	for v := from; v < CurrentVersion; v++ {
+		if err := migrations[v].apply(raw); err != nil {
+			return from, fmt.Errorf("failed to migrate config to version %d (%s): %w", v+1, migrations[v].description, err)
+		}
+		raw["version"] = v + 1
+	}
+	return from, nil
+}
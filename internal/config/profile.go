@@ -0,0 +1,216 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gitkraken/gk-cli/internal/filelock"
+	"github.com/gitkraken/gk-cli/pkg/utils"
+)
+
+// DefaultProfile is the profile kept directly in the gk config directory
+const DefaultProfile = "default"
+
+// profileNamePattern restricts profile names to safe directory names
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// profile is the active profile, resolved by SetProfile
+var profile string
+
+// rootDir returns the gk config directory that holds all profiles
+func rootDir() (string, error) {
+	dir, err := utils.GetConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return dir, nil
+}
+
+// ProfileDir returns the directory holding a profile's config file and
+// workspaces. The default profile lives in the gk config directory itself,
+// so existing setups keep working.
+func ProfileDir(name string) (string, error) {
+	root, err := rootDir()
+	if err != nil {
+		return "", err
+	}
+	if name == "" || name == DefaultProfile {
+		return root, nil
+	}
+	return filepath.Join(root, "profiles", name), nil
+}
+
+// Dir returns the directory of the active profile
+func Dir() (string, error) {
+	return ProfileDir(Profile())
+}
+
+// Profile returns the name of the active profile
+func Profile() string {
+	if profile == "" {
+		return DefaultProfile
+	}
+	return profile
+}
+
+// SetProfile selects the active profile: name if given (from --profile),
+// otherwise GK_PROFILE, otherwise the one chosen with 'gk profile use'
+func SetProfile(name string) error {
+	if name == "" {
+		name = os.Getenv("GK_PROFILE")
+	}
+	if name == "" {
+		// A saved profile that was removed since falls back to the default
+		name = savedProfile()
+		if name != "" && !ProfileExists(name) {
+			fmt.Fprintf(os.Stderr, "⚠ Profile %q no longer exists, using the default profile\n", name)
+			name = ""
+		}
+	}
+	if name == "" {
+		name = DefaultProfile
+	}
+
+	if !ProfileExists(name) {
+		return fmt.Errorf("profile %q does not exist. Create it with 'gk profile create %s'", name, name)
+	}
+	profile = name
+	return nil
+}
+
+// ProfileExists reports whether a profile has been created
+func ProfileExists(name string) bool {
+	if name == DefaultProfile {
+		return true
+	}
+	if !profileNamePattern.MatchString(name) {
+		return false
+	}
+	dir, err := ProfileDir(name)
+	if err != nil {
+		return false
+	}
+	info, err := os.Stat(dir)
+	return err == nil && info.IsDir()
+}
+
+// Profiles returns the names of all profiles, sorted, starting with the
+// default profile
+func Profiles() ([]string, error) {
+	root, err := rootDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(filepath.Join(root, "profiles"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to list profiles: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() && profileNamePattern.MatchString(entry.Name()) && entry.Name() != DefaultProfile {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return append([]string{DefaultProfile}, names...), nil
+}
+
+// CreateProfile creates an empty profile
+func CreateProfile(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use letters, digits, '-' and '_'", name)
+	}
+	if ProfileExists(name) {
+		return fmt.Errorf("profile %q already exists", name)
+	}
+	dir, err := ProfileDir(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create profile directory: %w", err)
+	}
+	return nil
+}
+
+// UseProfile makes a profile the one used when neither --profile nor
+// GK_PROFILE is given
+func UseProfile(name string) error {
+	if !ProfileExists(name) {
+		return fmt.Errorf("profile %q does not exist. Create it with 'gk profile create %s'", name, name)
+	}
+	path, err := profileFile()
+	if err != nil {
+		return err
+	}
+	if err := filelock.WriteFile(path, []byte(name+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to save current profile: %w", err)
+	}
+	return nil
+}
+
+// savedProfile returns the profile chosen with 'gk profile use', if any
+func savedProfile() string {
+	path, err := profileFile()
+	if err != nil {
+		return ""
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// profileFile is where 'gk profile use' records its choice
+func profileFile() (string, error) {
+	root, err := rootDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, "profile"), nil
+}
+
+// secretKey namespaces a credential store key by profile, so profiles don't
+// share tokens. A config file chosen with --config or GK_CONFIG gets its own
+// namespace, so it doesn't replace the profile's tokens. The default
+// profile keeps the plain keys.
+func secretKey(key string) string {
+	return secretNamespace() + key
+}
+
+// secretNamespace returns the prefix of the credential store keys that
+// belong to the config file in use
+func secretNamespace() string {
+	if path := otherConfigFile(); path != "" {
+		sum := sha256.Sum256([]byte(path))
+		return "files." + hex.EncodeToString(sum[:8]) + "."
+	}
+	if Profile() == DefaultProfile {
+		return ""
+	}
+	return "profiles." + Profile() + "."
+}
+
+// otherConfigFile returns the absolute path of the config file set with
+// SetFile, or "" if none is set or it is the active profile's own file
+func otherConfigFile() string {
+	if configFile == "" {
+		return ""
+	}
+	path, err := filepath.Abs(configFile)
+	if err != nil {
+		path = configFile
+	}
+	if dir, err := Dir(); err == nil && path == filepath.Join(dir, "config.yaml") {
+		return ""
+	}
+	return path
+}
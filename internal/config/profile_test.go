@@ -0,0 +1,47 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProfiles(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GK_PROFILE", "")
+	defer func() { profile = "" }()
+
+	if err := SetProfile("acme"); err == nil {
+		t.Error("expected a missing profile to be rejected")
+	}
+	if err := CreateProfile("acme"); err != nil {
+		t.Fatal(err)
+	}
+	if err := CreateProfile("../acme"); err == nil {
+		t.Error("expected an invalid profile name to be rejected")
+	}
+	if got, _ := Profiles(); !reflect.DeepEqual(got, []string{"default", "acme"}) {
+		t.Errorf("Profiles = %v", got)
+	}
+
+	if err := SetProfile(""); err != nil || Profile() != DefaultProfile {
+		t.Fatalf("SetProfile = %v, profile %q", err, Profile())
+	}
+	if secretKey("auth.token") != "auth.token" {
+		t.Error("the default profile should keep plain secret keys")
+	}
+
+	if err := UseProfile("acme"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetProfile(""); err != nil || Profile() != "acme" {
+		t.Fatalf("SetProfile = %v, profile %q", err, Profile())
+	}
+	if got := secretKey("auth.token"); got != "profiles.acme.auth.token" {
+		t.Errorf("secretKey = %q", got)
+	}
+
+	t.Setenv("GK_PROFILE", DefaultProfile)
+	if err := SetProfile(""); err != nil || Profile() != DefaultProfile {
+		t.Errorf("GK_PROFILE should override the saved profile, got %q", Profile())
+	}
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gitkraken/gk-cli/internal/credentials"
+)
+
+// providerSecrets returns pointers to the provider settings kept in the
+// credential store instead of the config file, by field name
+func providerSecrets(p *ProviderConfig) map[string]*string {
+	return map[string]*string{
+		"token":         &p.Token,
+		"refresh_token": &p.RefreshToken,
+		"password":      &p.Password,
+	}
+}
+
+var (
+	secretStore credentials.Store
+	// storedSecrets are the secrets currently referenced by the config file
+	storedSecrets = make(map[string]string)
+)
+
+func getSecretStore() credentials.Store {
+	if secretStore == nil {
+		secretStore = credentials.Default()
+	}
+	return secretStore
+}
+
+// SecretStoreName describes where secrets are stored
+func SecretStoreName() string {
+	return getSecretStore().Name()
+}
+
+// resolveSecrets replaces references in cfg with the stored secrets and
+// returns how many plaintext secrets were found, which need migrating
+func resolveSecrets(cfg *Config) (int, error) {
+	plaintext := 0
+	resolve := func(key string, value *string) error {
+		if *value == "" {
+			return nil
+		}
+		ref, ok := credentials.ParseRef(*value)
+		if !ok {
+			plaintext++
+			return nil
+		}
+		secret, err := getSecretStore().Get(ref)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", key, err)
+		}
+		storedSecrets[ref] = secret
+		*value = secret
+		return nil
+	}
+
+	if err := resolve("auth.token", &cfg.Auth.Token); err != nil {
+		return 0, err
+	}
+	if err := resolve("auth.refresh_token", &cfg.Auth.RefreshToken); err != nil {
+		return 0, err
+	}
+	for name, provider := range cfg.Providers {
+		for field, value := range providerSecrets(&provider) {
+			if err := resolve("providers."+name+"."+field, value); err != nil {
+				return 0, err
+			}
+		}
+		cfg.Providers[name] = provider
+	}
+	return plaintext, nil
+}
+
+// storeSecrets saves the secrets in cfg to the credential store and returns
+// a copy of cfg to write to the config file, with the secrets replaced by
+// references. Secrets no longer in cfg are deleted. References that were
+// never resolved, such as when the store was unavailable, are written back
+// as they are rather than stored as the secret.
+func storeSecrets(cfg *Config) (*Config, error) {
+	current := make(map[string]string)
+	store := func(key string, value *string) error {
+		if *value == "" {
+			return nil
+		}
+		if ref, ok := credentials.ParseRef(*value); ok {
+			if stored, ok := storedSecrets[ref]; ok {
+				current[ref] = stored
+			}
+			return nil
+		}
+		key = secretKey(key)
+		if stored, ok := storedSecrets[key]; !ok || stored != *value {
+			if err := getSecretStore().Set(key, *value); err != nil {
+				return fmt.Errorf("failed to store %s: %w", key, err)
+			}
+		}
+		current[key] = *value
+		*value = credentials.Ref(key)
+		return nil
+	}
+
+	doc := *cfg
+	if err := store("auth.token", &doc.Auth.Token); err != nil {
+		return nil, err
+	}
+	if err := store("auth.refresh_token", &doc.Auth.RefreshToken); err != nil {
+		return nil, err
+	}
+
+	doc.Providers = make(map[string]ProviderConfig, len(cfg.Providers))
+	for name, provider := range cfg.Providers {
+		for field, value := range providerSecrets(&provider) {
+			if err := store("providers."+name+"."+field, value); err != nil {
+				return nil, err
+			}
+		}
+		doc.Providers[name] = provider
+	}
+
+	for key := range storedSecrets {
+		if _, ok := current[key]; ok {
+			continue
+		}
+		// A file that used to share another namespace's secrets must not
+		// delete them
+		if !strings.HasPrefix(key, secretNamespace()) {
+			continue
+		}
+		if err := getSecretStore().Delete(key); err != nil && !errors.Is(err, credentials.ErrNotFound) {
+			return nil, fmt.Errorf("failed to delete %s: %w", key, err)
+		}
+	}
+	storedSecrets = current
+
+	return &doc, nil
+}
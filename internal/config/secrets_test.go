@@ -0,0 +1,176 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gitkraken/gk-cli/internal/credentials"
+	"github.com/spf13/viper"
+)
+
+func TestSecretsMigrateAndResolve(t *testing.T) {
+	secretStore = credentials.NewFileStore(t.TempDir())
+	storedSecrets = make(map[string]string)
+	defer func() { secretStore = nil }()
+
+	// A config file written before secrets moved to the store
+	cfg := &Config{
+		Auth: AuthConfig{Token: "gk-token", ExpiresAt: "2030-01-01T00:00:00Z"},
+		Providers: map[string]ProviderConfig{
+			"github":    {Token: "ghp_secret"},
+			"bitbucket": {Username: "alice", Password: "app-pass"},
+		},
+	}
+	plaintext, err := resolveSecrets(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plaintext != 3 {
+		t.Errorf("expected 3 plaintext secrets, got %d", plaintext)
+	}
+
+	doc, err := storeSecrets(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Auth.Token != credentials.Ref("auth.token") || doc.Auth.RefreshToken != "" {
+		t.Errorf("unexpected auth section: %+v", doc.Auth)
+	}
+	bitbucket := doc.Providers["bitbucket"]
+	if bitbucket.Username != "alice" || bitbucket.Password != credentials.Ref("providers.bitbucket.password") {
+		t.Errorf("unexpected bitbucket section: %+v", bitbucket)
+	}
+	if cfg.Providers["github"].Token != "ghp_secret" {
+		t.Errorf("in-memory config should keep the secret")
+	}
+
+	// Reading the written config back resolves the references
+	loaded := &Config{
+		Auth:      doc.Auth,
+		Providers: doc.Providers,
+	}
+	if plaintext, err := resolveSecrets(loaded); err != nil || plaintext != 0 {
+		t.Fatalf("resolve failed: %d plaintext, %v", plaintext, err)
+	}
+	if loaded.Auth.Token != "gk-token" || loaded.Providers["github"].Token != "ghp_secret" {
+		t.Errorf("secrets not resolved: %+v", loaded)
+	}
+
+	// Removing a provider deletes its secret
+	delete(loaded.Providers, "github")
+	if _, err := storeSecrets(loaded); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := secretStore.Get("providers.github.token"); err != credentials.ErrNotFound {
+		t.Errorf("expected removed provider's secret to be deleted, got %v", err)
+	}
+}
+
+func TestStoreSecretsKeepsUnresolvedReferences(t *testing.T) {
+	secretStore = credentials.NewFileStore(t.TempDir())
+	storedSecrets = make(map[string]string)
+	defer func() { secretStore = nil }()
+
+	if err := secretStore.Set("providers.github.token", "ghp_secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The store couldn't be read at startup, so the reference is still there
+	cfg := &Config{
+		Providers: map[string]ProviderConfig{
+			"github": {Token: credentials.Ref("providers.github.token")},
+		},
+	}
+	doc, err := storeSecrets(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Providers["github"].Token != credentials.Ref("providers.github.token") {
+		t.Errorf("expected the reference to be written back, got %q", doc.Providers["github"].Token)
+	}
+	if secret, err := secretStore.Get("providers.github.token"); err != nil || secret != "ghp_secret" {
+		t.Errorf("expected the stored secret to be left alone, got %q, %v", secret, err)
+	}
+}
+
+func TestInitUpgradeKeepsStoredSecrets(t *testing.T) {
+	secretStore = credentials.NewFileStore(t.TempDir())
+	storedSecrets = make(map[string]string)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	SetFile(path)
+	defer func() {
+		secretStore = nil
+		SetFile("")
+		viper.Reset()
+		configPath, globalConfig, effective = "", nil, nil
+	}()
+
+	// A version 0 file written after secrets moved to the store
+	for key, secret := range map[string]string{"auth.token": "gk-token", "providers.github.token": "ghp_secret"} {
+		if err := secretStore.Set(key, secret); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data := "auth:\n  token: " + credentials.Ref("auth.token") + "\n" +
+		"providers:\n  github:\n    token: " + credentials.Ref("providers.github.token") + "\n"
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	for key, want := range map[string]string{"auth.token": "gk-token", "providers.github.token": "ghp_secret"} {
+		if got, err := secretStore.Get(key); err != nil || got != want {
+			t.Errorf("expected %s to keep %q in the store, got %q, %v", key, want, got, err)
+		}
+	}
+	if Get().Providers["github"].Token != "ghp_secret" {
+		t.Errorf("expected the provider token to be resolved, got %q", Get().Providers["github"].Token)
+	}
+	upgraded, _ := os.ReadFile(path)
+	if !strings.Contains(string(upgraded), "version: 1") || strings.Contains(string(upgraded), "gk-token") {
+		t.Errorf("expected an upgraded file holding references, got:\n%s", upgraded)
+	}
+}
+
+func TestConfigFileSecretsAreNotShared(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	secretStore = credentials.NewFileStore(t.TempDir())
+	storedSecrets = make(map[string]string)
+	SetFile(filepath.Join(t.TempDir(), "ci.yaml"))
+	defer func() {
+		secretStore = nil
+		SetFile("")
+	}()
+
+	if err := secretStore.Set("providers.github.token", "ghp_user"); err != nil {
+		t.Fatal(err)
+	}
+	// Written by an older gk, the file still references the shared key
+	storedSecrets["providers.github.token"] = "ghp_user"
+
+	cfg := &Config{Providers: map[string]ProviderConfig{"github": {Token: "ghp_ci"}}}
+	doc, err := storeSecrets(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ref, _ := credentials.ParseRef(doc.Providers["github"].Token)
+	if !strings.HasPrefix(ref, "files.") {
+		t.Errorf("expected the token to be stored in the file's namespace, got %q", ref)
+	}
+	if secret, err := secretStore.Get("providers.github.token"); err != nil || secret != "ghp_user" {
+		t.Errorf("expected the default config's token to be left alone, got %q, %v", secret, err)
+	}
+
+	// The profile's own file, even when named with --config, is not another file
+	dir, _ := Dir()
+	SetFile(filepath.Join(dir, "config.yaml"))
+	if got := secretKey("auth.token"); got != "auth.token" {
+		t.Errorf("secretKey = %q, want auth.token", got)
+	}
+}
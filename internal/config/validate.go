@@ -0,0 +1,279 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gitkraken/gk-cli/internal/credentials"
+	"gopkg.in/yaml.v3"
+)
+
+// Issue is a problem found in a config file
+type Issue struct {
+	Line    int
+	Column  int
+	Path    string
+	Message string
+}
+
+func (i Issue) String() string {
+	if i.Path == "" {
+		return fmt.Sprintf("line %d: %s", i.Line, i.Message)
+	}
+	return fmt.Sprintf("line %d: %s: %s", i.Line, i.Path, i.Message)
+}
+
+// Validate checks a config file against the schema and reports unknown
+// keys, values of the wrong type and missing required fields, in file
+// order. A file that is not valid YAML returns an error.
+func Validate(data []byte) ([]Issue, error) {
+	return validate(data, reflect.TypeOf(Config{}))
+}
+
+// ValidateRepo checks a repository's .gk.yaml the same way
+func ValidateRepo(data []byte) ([]Issue, error) {
+	return validate(data, reflect.TypeOf(RepoConfig{}))
+}
+
+func validate(data []byte, schema reflect.Type) ([]Issue, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+
+	v := &validator{}
+	root := doc.Content[0]
+	v.check(root, schema, "")
+	if len(v.issues) == 0 && schema == reflect.TypeOf(Config{}) {
+		// Semantic checks only make sense once the shape is right
+		var cfg Config
+		if err := root.Decode(&cfg); err != nil {
+			return nil, fmt.Errorf("failed to decode config: %w", err)
+		}
+		v.checkSemantics(root, &cfg)
+	}
+
+	sort.SliceStable(v.issues, func(i, j int) bool {
+		if v.issues[i].Line != v.issues[j].Line {
+			return v.issues[i].Line < v.issues[j].Line
+		}
+		return v.issues[i].Column < v.issues[j].Column
+	})
+	return v.issues, nil
+}
+
+type validator struct {
+	issues []Issue
+}
+
+func (v *validator) add(node *yaml.Node, path, format string, args ...interface{}) {
+	v.issues = append(v.issues, Issue{
+		Line:    node.Line,
+		Column:  node.Column,
+		Path:    path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// check validates the shape of a node against a Go type
+func (v *validator) check(node *yaml.Node, t reflect.Type, path string) {
+	if node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	if node.Tag == "!!null" {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if node.Kind != yaml.MappingNode {
+			v.add(node, path, "expected a mapping, got %s", describeNode(node))
+			return
+		}
+		seen := make(map[string]bool)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			field, ok := fieldByKey(t, key.Value)
+			if !ok {
+				v.add(key, joinPath(path, key.Value), "unknown key")
+				continue
+			}
+			seen[key.Value] = true
+			v.check(value, field.Type, joinPath(path, key.Value))
+		}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := yamlName(field)
+			if field.Tag.Get("required") == "true" && !seen[name] {
+				v.add(node, path, "missing required key %q", name)
+			}
+		}
+
+	case reflect.Map:
+		if node.Kind != yaml.MappingNode {
+			v.add(node, path, "expected a mapping, got %s", describeNode(node))
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			v.check(value, t.Elem(), joinPath(path, key.Value))
+		}
+
+	case reflect.Slice:
+		if node.Kind != yaml.SequenceNode {
+			v.add(node, path, "expected a list, got %s", describeNode(node))
+			return
+		}
+		for i, item := range node.Content {
+			v.check(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))
+		}
+
+	case reflect.String:
+		if node.Kind != yaml.ScalarNode {
+			v.add(node, path, "expected a string, got %s", describeNode(node))
+		}
+
+	case reflect.Int:
+		if node.Kind != yaml.ScalarNode || node.Tag != "!!int" {
+			v.add(node, path, "expected an integer, got %s", describeNode(node))
+		}
+	}
+}
+
+// checkSemantics validates values that are well-formed but not meaningful
+func (v *validator) checkSemantics(root *yaml.Node, cfg *Config) {
+	if cfg.Version > CurrentVersion {
+		v.add(valueNode(root, "version"), "version", "version %d is newer than this gk supports (%d)", cfg.Version, CurrentVersion)
+	}
+
+	providers := valueNode(root, "providers")
+	for key, p := range cfg.Providers {
+		node := valueNode(providers, key)
+		path := "providers." + key
+		name, _, _ := strings.Cut(key, "@")
+		switch name {
+		case "github", "gitlab", "bitbucket":
+		default:
+			v.add(keyNode(providers, key), path, "unknown provider %q (supported: github, gitlab, bitbucket)", name)
+			continue
+		}
+
+		switch p.Auth {
+		case ProviderAuthDynamic:
+			if p.Source == "" {
+				v.add(node, path, "missing required key \"source\" for dynamic auth")
+			} else if !contains(credentials.Sources, p.Source) {
+				v.add(valueNode(node, "source"), path+".source", "unknown source %q (supported: %s)", p.Source, strings.Join(credentials.Sources, ", "))
+			}
+		case ProviderAuthToken, ProviderAuthOAuth:
+			if name == "bitbucket" {
+				if p.Username == "" {
+					v.add(node, path, "missing required key \"username\"")
+				}
+				if p.Password == "" {
+					v.add(node, path, "missing required key \"password\"")
+				}
+			} else if p.Token == "" {
+				v.add(node, path, "missing required key \"token\"")
+			}
+		default:
+			v.add(valueNode(node, "auth"), path+".auth", "unknown auth type %q (supported: oauth, dynamic)", p.Auth)
+		}
+	}
+
+	if cfg.Account != "" {
+		if _, ok := cfg.Providers[cfg.Account]; !ok {
+			v.add(valueNode(root, "account"), "account", "provider account %q is not configured", cfg.Account)
+		}
+	}
+
+	routes := valueNode(root, "routes")
+	for i, r := range cfg.Routes {
+		if r.Account == "" {
+			continue
+		}
+		if _, ok := cfg.Providers[r.Account]; !ok {
+			node := routes
+			if routes != nil && i < len(routes.Content) {
+				node = valueNode(routes.Content[i], "account")
+			}
+			v.add(node, fmt.Sprintf("routes[%d].account", i), "provider account %q is not configured", r.Account)
+		}
+	}
+}
+
+// fieldByKey returns the struct field with the given yaml key
+func fieldByKey(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if yamlName(t.Field(i)) == key {
+			return t.Field(i), true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func yamlName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+	return name
+}
+
+// keyNode and valueNode find a key and its value in a mapping node, falling
+// back to the mapping itself so issues still have a line number
+func keyNode(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping != nil {
+		for i := 0; i+1 < len(mapping.Content); i += 2 {
+			if mapping.Content[i].Value == key {
+				return mapping.Content[i]
+			}
+		}
+	}
+	return mapping
+}
+
+func valueNode(mapping *yaml.Node, key string) *yaml.Node {
+	if mapping != nil {
+		for i := 0; i+1 < len(mapping.Content); i += 2 {
+			if mapping.Content[i].Value == key {
+				return mapping.Content[i+1]
+			}
+		}
+	}
+	return mapping
+}
+
+func describeNode(node *yaml.Node) string {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return "a mapping"
+	case yaml.SequenceNode:
+		return "a list"
+	}
+	switch node.Tag {
+	case "!!int", "!!float":
+		return "number " + node.Value
+	case "!!bool":
+		return "boolean " + node.Value
+	}
+	return fmt.Sprintf("%q", node.Value)
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	data := []byte(`version: 1
+theme: default
+colour: blue
+providers:
+  github:
+    tokn: ghp_x
+  gitlab:
+    token: [a, b]
+  bitbucket@work:
+    username: alice
+routes:
+  - match: github.com/acme/*
+    account: github@work
+  - account: gitlab
+`)
+	issues, err := Validate(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+	want := []string{
+		`line 3: colour: unknown key`,
+		`line 6: providers.github.tokn: unknown key`,
+		`line 8: providers.gitlab.token: expected a string, got a list`,
+		`line 14: routes[1]: missing required key "match"`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestValidateSemantics(t *testing.T) {
+	data := []byte(`version: 1
+providers:
+  github:
+    auth: dynamic
+  bitbucket:
+    username: alice
+routes:
+  - match: github.com/acme/*
+    account: github@work
+`)
+	issues, err := Validate(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.String())
+	}
+	want := []string{
+		`line 4: providers.github: missing required key "source" for dynamic auth`,
+		`line 6: providers.bitbucket: missing required key "password"`,
+		`line 9: routes[0].account: provider account "github@work" is not configured`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("unexpected issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	configPath = filepath.Join(t.TempDir(), "config.yaml")
+	defer func() { configPath = "" }()
+
+	raw := map[string]interface{}{
+		"theme":      "dark",
+		"workspaces": map[string]interface{}{"app": map[string]interface{}{"path": "/src/app"}},
+	}
+	from, err := migrate(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from != 0 || raw["version"] != CurrentVersion {
+		t.Errorf("expected migration from 0 to %d, got from %d to %v", CurrentVersion, from, raw["version"])
+	}
+	if _, ok := raw["workspaces"]; ok {
+		t.Error("expected workspaces section to be removed from the config")
+	}
+	kept, err := os.ReadFile(filepath.Join(filepath.Dir(configPath), "config.workspaces.yaml"))
+	if err != nil || !strings.Contains(string(kept), "/src/app") {
+		t.Errorf("expected the workspaces section to be kept next to the config, got %q, %v", kept, err)
+	}
+	if len(migrations) != CurrentVersion {
+		t.Errorf("CurrentVersion is %d but there are %d migrations", CurrentVersion, len(migrations))
+	}
+
+	if _, err := migrate(map[string]interface{}{"version": CurrentVersion + 1}); err == nil {
+		t.Error("expected an error for a newer config version")
+	}
+}
@@ -0,0 +1,102 @@
+package credentials
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+// ServiceName identifies gk entries in the OS keyring
+const ServiceName = "gk-cli"
+
+// RefPrefix marks a config value that refers to a stored secret
+const RefPrefix = "secret://"
+
+// ErrNotFound is returned when a secret does not exist in the store
+var ErrNotFound = errors.New("secret not found")
+
+// Store keeps secrets outside the config file
+type Store interface {
+	// Name describes where secrets are kept, for messages
+	Name() string
+	Get(key string) (string, error)
+	Set(key, value string) error
+	Delete(key string) error
+}
+
+// Default returns the OS keyring (Keychain, Secret Service or Credential
+// Manager), falling back to an encrypted file when no keyring is available.
+// GK_CREDENTIAL_STORE=keyring|file forces one of them.
+func Default() Store {
+	switch strings.ToLower(os.Getenv("GK_CREDENTIAL_STORE")) {
+	case "keyring":
+		return keyringStore{}
+	case "file":
+		return NewFileStore("")
+	}
+	return &fallbackStore{primary: keyringStore{}, fallback: NewFileStore("")}
+}
+
+// Ref returns the config value referring to the secret stored under key
+func Ref(key string) string {
+	return RefPrefix + key
+}
+
+// ParseRef returns the key a config value refers to, if it is a reference
+func ParseRef(value string) (string, bool) {
+	if !strings.HasPrefix(value, RefPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(value, RefPrefix), true
+}
+
+// fallbackStore uses the primary store until it fails, then the fallback
+type fallbackStore struct {
+	primary     Store
+	fallback    Store
+	unavailable bool
+}
+
+func (s *fallbackStore) Name() string {
+	if s.unavailable {
+		return s.fallback.Name()
+	}
+	return s.primary.Name()
+}
+
+func (s *fallbackStore) Get(key string) (string, error) {
+	if !s.unavailable {
+		value, err := s.primary.Get(key)
+		if err == nil {
+			return value, nil
+		}
+		if !errors.Is(err, ErrNotFound) {
+			s.unavailable = true
+		}
+	}
+	return s.fallback.Get(key)
+}
+
+func (s *fallbackStore) Set(key, value string) error {
+	if !s.unavailable {
+		if err := s.primary.Set(key, value); err == nil {
+			return nil
+		}
+		s.unavailable = true
+	}
+	return s.fallback.Set(key, value)
+}
+
+func (s *fallbackStore) Delete(key string) error {
+	// Secrets may be in either store if the keyring was unavailable earlier
+	primaryErr := s.primary.Delete(key)
+	fallbackErr := s.fallback.Delete(key)
+	switch {
+	case primaryErr == nil || fallbackErr == nil:
+		return nil
+	case !errors.Is(primaryErr, ErrNotFound):
+		return primaryErr
+	default:
+		return fallbackErr
+	}
+}
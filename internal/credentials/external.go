@@ -0,0 +1,161 @@
+package credentials
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// External token sources that provider credentials can be imported from
+const (
+	SourceGH            = "gh"
+	SourceGlab          = "glab"
+	SourceGitCredential = "git-credential"
+	SourceEnv           = "env"
+)
+
+// Sources lists the supported external token sources
+var Sources = []string{SourceGH, SourceGlab, SourceGitCredential, SourceEnv}
+
+// providerHosts are the hosts tokens are looked up for
+var providerHosts = map[string]string{
+	"github": "github.com",
+	"gitlab": "gitlab.com",
+}
+
+// providerEnv are the environment variables checked by the env source, in
+// order of preference
+var providerEnv = map[string][]string{
+	"github": {"GITHUB_TOKEN", "GH_TOKEN"},
+	"gitlab": {"GITLAB_TOKEN"},
+}
+
+// runCommand runs an external command with stdin and returns its stdout
+var runCommand = func(stdin string, name string, args ...string) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	// Never let git or gh prompt on the terminal for missing credentials
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GCM_INTERACTIVE=never")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %s", name, msg)
+		}
+		return nil, fmt.Errorf("failed to run %s: %w", name, err)
+	}
+	return out, nil
+}
+
+// ExternalToken reads a provider's token from another tool: the gh CLI,
+// glab's config file, a git credential helper or environment variables
+func ExternalToken(source, provider string) (string, error) {
+	host, ok := providerHosts[provider]
+	if !ok {
+		return "", fmt.Errorf("importing credentials is not supported for %s (supported: github, gitlab)", provider)
+	}
+
+	var token string
+	var err error
+	switch source {
+	case SourceGH:
+		if provider != "github" {
+			return "", fmt.Errorf("gh only provides GitHub tokens")
+		}
+		token, err = ghToken(host)
+	case SourceGlab:
+		if provider != "gitlab" {
+			return "", fmt.Errorf("glab only provides GitLab tokens")
+		}
+		token, err = glabToken(host)
+	case SourceGitCredential:
+		token, err = gitCredentialToken(host)
+	case SourceEnv:
+		token = envToken(provider)
+		if token == "" {
+			err = fmt.Errorf("none of %s are set", strings.Join(providerEnv[provider], ", "))
+		}
+	default:
+		return "", fmt.Errorf("unknown credential source: %s (supported: %s)", source, strings.Join(Sources, ", "))
+	}
+	if err != nil {
+		return "", err
+	}
+	if token == "" {
+		return "", fmt.Errorf("no %s token found in %s", provider, source)
+	}
+	return token, nil
+}
+
+// ghToken asks the gh CLI for its token
+func ghToken(host string) (string, error) {
+	out, err := runCommand("", "gh", "auth", "token", "--hostname", host)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// glabToken reads the token for host from glab's config file
+func glabToken(host string) (string, error) {
+	path := glabConfigPath()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read glab config: %w", err)
+	}
+
+	var cfg struct {
+		Hosts map[string]struct {
+			Token string `yaml:"token"`
+		} `yaml:"hosts"`
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return "", fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return cfg.Hosts[host].Token, nil
+}
+
+// glabConfigPath returns the location of glab's config file
+func glabConfigPath() string {
+	if dir := os.Getenv("GLAB_CONFIG_DIR"); dir != "" {
+		return filepath.Join(dir, "config.yml")
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "glab-cli", "config.yml")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "glab-cli", "config.yml")
+}
+
+// gitCredentialToken asks the configured git credential helpers for the
+// password stored for https://host
+func gitCredentialToken(host string) (string, error) {
+	out, err := runCommand("protocol=https\nhost="+host+"\n\n", "git", "credential", "fill")
+	if err != nil {
+		return "", err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), "password="); ok {
+			return value, nil
+		}
+	}
+	return "", nil
+}
+
+// envToken returns the first provider token set in the environment
+func envToken(provider string) string {
+	for _, name := range providerEnv[provider] {
+		if token := os.Getenv(name); token != "" {
+			return token
+		}
+	}
+	return ""
+}
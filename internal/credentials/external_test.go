@@ -0,0 +1,62 @@
+package credentials
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExternalToken(t *testing.T) {
+	origRun := runCommand
+	defer func() { runCommand = origRun }()
+	runCommand = func(stdin string, name string, args ...string) ([]byte, error) {
+		switch name {
+		case "gh":
+			return []byte("gho_fromgh\n"), nil
+		case "git":
+			if !strings.Contains(stdin, "host=gitlab.com") {
+				t.Errorf("unexpected credential request %q", stdin)
+			}
+			return []byte("protocol=https\nhost=gitlab.com\nusername=oauth2\npassword=glpat-fromgit\n"), nil
+		}
+		t.Fatalf("unexpected command %s", name)
+		return nil, nil
+	}
+
+	dir := t.TempDir()
+	t.Setenv("GLAB_CONFIG_DIR", dir)
+	glabConfig := "hosts:\n  gitlab.com:\n    token: glpat-fromglab\n    api_protocol: https\n"
+	if err := os.WriteFile(filepath.Join(dir, "config.yml"), []byte(glabConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GITLAB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "ghp_fromenv")
+
+	tests := []struct {
+		source, provider, want string
+	}{
+		{SourceGH, "github", "gho_fromgh"},
+		{SourceGlab, "gitlab", "glpat-fromglab"},
+		{SourceGitCredential, "gitlab", "glpat-fromgit"},
+		{SourceEnv, "github", "ghp_fromenv"},
+	}
+	for _, tt := range tests {
+		got, err := ExternalToken(tt.source, tt.provider)
+		if err != nil {
+			t.Errorf("ExternalToken(%s, %s): %v", tt.source, tt.provider, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ExternalToken(%s, %s) = %q, want %q", tt.source, tt.provider, got, tt.want)
+		}
+	}
+
+	if _, err := ExternalToken(SourceEnv, "gitlab"); err == nil {
+		t.Error("expected an error when GITLAB_TOKEN is not set")
+	}
+	if _, err := ExternalToken(SourceGH, "gitlab"); err == nil {
+		t.Error("expected an error for gh with gitlab")
+	}
+}
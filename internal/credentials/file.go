@@ -0,0 +1,188 @@
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/gitkraken/gk-cli/internal/filelock"
+	"github.com/gitkraken/gk-cli/pkg/utils"
+)
+
+// FileStore keeps secrets AES-GCM encrypted in credentials.json, for
+// machines without a keyring. The key is derived from GK_CREDENTIALS_KEY
+// when set, otherwise it is generated once and kept in credentials.key,
+// readable only by the current user.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore returns a file store in dir, or the config directory if
+// dir is empty
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{dir: dir}
+}
+
+func (s *FileStore) Name() string {
+	return "an encrypted file"
+}
+
+func (s *FileStore) paths() (data, key string, err error) {
+	dir := s.dir
+	if dir == "" {
+		if dir, err = utils.GetConfigDir(); err != nil {
+			return "", "", fmt.Errorf("failed to get config directory: %w", err)
+		}
+	}
+	return filepath.Join(dir, "credentials.json"), filepath.Join(dir, "credentials.key"), nil
+}
+
+func (s *FileStore) Get(key string) (string, error) {
+	secrets, err := s.load()
+	if err != nil {
+		return "", err
+	}
+	sealed, ok := secrets[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+
+	aead, err := s.cipher(false)
+	if err != nil {
+		return "", err
+	}
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil || len(data) < aead.NonceSize() {
+		return "", fmt.Errorf("corrupt credential %s", key)
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(key))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt credential %s: %w", key, err)
+	}
+	return string(plaintext), nil
+}
+
+func (s *FileStore) Set(key, value string) error {
+	return s.update(func(secrets map[string]string) error {
+		aead, err := s.cipher(true)
+		if err != nil {
+			return err
+		}
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+			return fmt.Errorf("failed to generate nonce: %w", err)
+		}
+		secrets[key] = base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, []byte(value), []byte(key)))
+		return nil
+	})
+}
+
+func (s *FileStore) Delete(key string) error {
+	return s.update(func(secrets map[string]string) error {
+		if _, ok := secrets[key]; !ok {
+			return ErrNotFound
+		}
+		delete(secrets, key)
+		return nil
+	})
+}
+
+func (s *FileStore) load() (map[string]string, error) {
+	path, _, err := s.paths()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read credentials: %w", err)
+	}
+	return parseSecrets(data)
+}
+
+// update applies fn to the stored secrets and saves them atomically. The
+// file is locked from reading to writing, so gk processes changing secrets
+// at the same time don't lose each other's changes.
+func (s *FileStore) update(fn func(secrets map[string]string) error) error {
+	path, _, err := s.paths()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	err = filelock.Update(path, 0600, func(data []byte) ([]byte, error) {
+		secrets, err := parseSecrets(data)
+		if err != nil {
+			return nil, err
+		}
+		if err := fn(secrets); err != nil {
+			return nil, err
+		}
+		data, err = json.MarshalIndent(secrets, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal credentials: %w", err)
+		}
+		return data, nil
+	})
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("failed to write credentials: %w", err)
+	}
+	return err
+}
+
+func parseSecrets(data []byte) (map[string]string, error) {
+	secrets := make(map[string]string)
+	if len(data) == 0 {
+		return secrets, nil
+	}
+	if err := json.Unmarshal(data, &secrets); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials: %w", err)
+	}
+	return secrets, nil
+}
+
+// cipher returns the AEAD for the store's key, generating the key file if
+// create is set and no key exists yet
+func (s *FileStore) cipher(create bool) (cipher.AEAD, error) {
+	var key []byte
+	if passphrase := os.Getenv("GK_CREDENTIALS_KEY"); passphrase != "" {
+		sum := sha256.Sum256([]byte(passphrase))
+		key = sum[:]
+	} else {
+		_, keyPath, err := s.paths()
+		if err != nil {
+			return nil, err
+		}
+		key, err = os.ReadFile(keyPath)
+		if os.IsNotExist(err) && create {
+			key = make([]byte, 32)
+			if _, err := io.ReadFull(rand.Reader, key); err != nil {
+				return nil, fmt.Errorf("failed to generate credentials key: %w", err)
+			}
+			if err := filelock.WriteFile(keyPath, key, 0600); err != nil {
+				return nil, fmt.Errorf("failed to write credentials key: %w", err)
+			}
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read credentials key: %w", err)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("invalid credentials key in %s", keyPath)
+		}
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
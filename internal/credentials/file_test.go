@@ -0,0 +1,86 @@
+package credentials
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFileStoreEncryptsSecrets(t *testing.T) {
+	dir := t.TempDir()
+	store := NewFileStore(dir)
+
+	if err := store.Set("providers.github.token", "ghp_secret"); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "credentials.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "ghp_secret") {
+		t.Errorf("secret stored in plaintext:\n%s", data)
+	}
+	info, err := os.Stat(filepath.Join(dir, "credentials.key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected key file mode 0600, got %v", info.Mode().Perm())
+	}
+
+	value, err := store.Get("providers.github.token")
+	if err != nil || value != "ghp_secret" {
+		t.Errorf("expected ghp_secret, got %q (%v)", value, err)
+	}
+
+	if err := store.Delete("providers.github.token"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := store.Get("providers.github.token"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got %v", err)
+	}
+}
+
+func TestParseRef(t *testing.T) {
+	if key, ok := ParseRef(Ref("auth.token")); !ok || key != "auth.token" {
+		t.Errorf("expected auth.token, got %q", key)
+	}
+	if _, ok := ParseRef("ghp_plaintext"); ok {
+		t.Errorf("plaintext value parsed as reference")
+	}
+}
+
+func TestFileStoreConcurrentSetsKeepEverySecret(t *testing.T) {
+	dir := t.TempDir()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// Separate stores, as separate gk processes would have
+			errs <- NewFileStore(dir).Set(fmt.Sprintf("key%d", i), fmt.Sprintf("value%d", i))
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+	}
+
+	store := NewFileStore(dir)
+	for i := 0; i < 10; i++ {
+		value, err := store.Get(fmt.Sprintf("key%d", i))
+		if err != nil || value != fmt.Sprintf("value%d", i) {
+			t.Errorf("key%d: expected value%d, got %q (%v)", i, i, value, err)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package credentials
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/zalando/go-keyring"
+)
+
+// keyringStore keeps secrets in the OS keyring
+type keyringStore struct{}
+
+func (keyringStore) Name() string {
+	return "the system keyring"
+}
+
+func (keyringStore) Get(key string) (string, error) {
+	value, err := keyring.Get(ServiceName, key)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read from keyring: %w", err)
+	}
+	return value, nil
+}
+
+func (keyringStore) Set(key, value string) error {
+	if err := keyring.Set(ServiceName, key, value); err != nil {
+		return fmt.Errorf("failed to write to keyring: %w", err)
+	}
+	return nil
+}
+
+func (keyringStore) Delete(key string) error {
+	err := keyring.Delete(ServiceName, key)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete from keyring: %w", err)
+	}
+	return nil
+}
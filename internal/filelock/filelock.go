@@ -0,0 +1,56 @@
+// Package filelock provides advisory file locks shared between gk processes,
+// and atomic file writes guarded by them.
+package filelock
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// pollInterval is how often a held lock is retried
+const pollInterval = 50 * time.Millisecond
+
+// errLocked is returned by tryLock when another process holds the lock
+var errLocked = errors.New("locked")
+
+// Lock is an exclusive advisory lock on a file
+type Lock struct {
+	file *os.File
+}
+
+// Acquire takes an exclusive lock on path, creating the file if needed, and
+// waits up to timeout for another process to release it
+func Acquire(path string, timeout time.Duration) (*Lock, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		err := tryLock(file)
+		if err == nil {
+			return &Lock{file: file}, nil
+		}
+		if !errors.Is(err, errLocked) {
+			file.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if time.Now().After(deadline) {
+			file.Close()
+			return nil, fmt.Errorf("timed out waiting for %s to be unlocked by another gk process", path)
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// Release releases the lock
+func (l *Lock) Release() error {
+	if err := unlock(l.file); err != nil {
+		l.file.Close()
+		return fmt.Errorf("failed to unlock: %w", err)
+	}
+	return l.file.Close()
+}
@@ -0,0 +1,64 @@
+package filelock
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAcquireIsExclusive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.lock")
+
+	first, err := Acquire(path, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Acquire(path, 100*time.Millisecond); err == nil {
+		t.Fatal("expected the second lock to time out")
+	}
+
+	// A waiter gets the lock once it is released
+	acquired := make(chan error)
+	go func() {
+		second, err := Acquire(path, 5*time.Second)
+		if err == nil {
+			err = second.Release()
+		}
+		acquired <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+	if err := first.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-acquired; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWriteFileReplacesAtomically(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteFile(path, []byte("new"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "new" {
+		t.Fatalf("ReadFile = %q, %v", data, err)
+	}
+	if info, _ := os.Stat(path); runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("permissions = %v, want 0600", info.Mode().Perm())
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".tmp") {
+			t.Errorf("temporary file %s was left behind", entry.Name())
+		}
+	}
+}
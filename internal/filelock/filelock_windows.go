@@ -0,0 +1,33 @@
+//go:build windows
+
+package filelock
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockRange covers the whole file
+const lockRange = ^uint32(0)
+
+func tryLock(file *os.File) error {
+	err := windows.LockFileEx(windows.Handle(file.Fd()),
+		windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY,
+		0, lockRange, lockRange, new(windows.Overlapped))
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return errLocked
+	}
+	return err
+}
+
+func unlock(file *os.File) error {
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, lockRange, lockRange, new(windows.Overlapped))
+}
+
+// syncDir is a no-op: directories can't be synced on Windows, where
+// renames are made durable by the file system
+func syncDir(dir string) error {
+	return nil
+}
@@ -0,0 +1,79 @@
+package filelock
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// writeLockTimeout bounds how long WriteFile waits for another writer
+const writeLockTimeout = 10 * time.Second
+
+// WriteFile replaces a file atomically: data goes to a temporary file in
+// the same directory, which is synced and renamed over path, so readers and
+// crashes never see a partial file. Concurrent writers are serialized by a
+// lock on path.lock. The file always ends up with perm, even if it existed
+// with wider permissions.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	return Update(path, perm, func([]byte) ([]byte, error) {
+		return data, nil
+	})
+}
+
+// Update replaces a file atomically like WriteFile with what update returns
+// for its current contents, which are nil if it doesn't exist yet. The lock
+// is held from reading to writing, so concurrent read-modify-write cycles
+// don't lose each other's changes. Nothing is written if update fails.
+func Update(path string, perm os.FileMode, update func(data []byte) ([]byte, error)) error {
+	// Replace the target of a symlinked file, not the link
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	lock, err := Acquire(path+".lock", writeLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	current, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	data, err := update(current)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to set permissions on %s: %w", path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return syncDir(dir)
+}
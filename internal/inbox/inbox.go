@@ -0,0 +1,242 @@
+package inbox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gitkraken/gk-cli/internal/api"
+	"github.com/gitkraken/gk-cli/internal/filelock"
+	"github.com/gitkraken/gk-cli/pkg/utils"
+)
+
+// State records notifications marked read or done locally, for providers
+// without server-side read state. Values are the RFC3339 time of the mark;
+// newer activity on a notification makes it show up again.
+type State struct {
+	Read map[string]string `json:"read"`
+	Done map[string]string `json:"done"`
+}
+
+// Options controls which notifications Load returns
+type Options struct {
+	All       bool
+	Reasons   []string
+	Providers []string
+	// Repos, keyed by provider, are scanned on providers without a
+	// server-side inbox
+	Repos map[string][]api.RepoRef
+}
+
+var statePath string
+
+func getStatePath() (string, error) {
+	if statePath != "" {
+		return statePath, nil
+	}
+	dir, err := utils.GetStateDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get state directory: %w", err)
+	}
+	return filepath.Join(dir, "inbox.json"), nil
+}
+
+// LoadState loads the local inbox state
+func LoadState() (*State, error) {
+	state := &State{Read: map[string]string{}, Done: map[string]string{}}
+
+	path, err := getStatePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read inbox state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse inbox state: %w", err)
+	}
+	if state.Read == nil {
+		state.Read = map[string]string{}
+	}
+	if state.Done == nil {
+		state.Done = map[string]string{}
+	}
+	return state, nil
+}
+
+// Save writes the local inbox state to disk
+func (s *State) Save() error {
+	path, err := getStatePath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal inbox state: %w", err)
+	}
+	if err := filelock.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write inbox state: %w", err)
+	}
+	return nil
+}
+
+// apply updates n from local marks and reports whether it should be hidden
+func (s *State) apply(n *api.Notification) bool {
+	key := n.Key()
+	if markedBefore(s.Done[key], n.UpdatedAt) {
+		n.Unread = false
+		return true
+	}
+	if markedBefore(s.Read[key], n.UpdatedAt) {
+		n.Unread = false
+	}
+	return false
+}
+
+// markedBefore reports whether a mark exists and is no older than the
+// notification's last update
+func markedBefore(markedAt, updatedAt string) bool {
+	if markedAt == "" {
+		return false
+	}
+	marked, err := time.Parse(time.RFC3339, markedAt)
+	if err != nil {
+		return false
+	}
+	updated, err := time.Parse(time.RFC3339, updatedAt)
+	if err != nil {
+		return true
+	}
+	return !updated.After(marked)
+}
+
+// Load gathers notifications from every requested provider, most recently
+// updated first. Providers that fail are reported in the returned map and
+// do not prevent the others from loading.
+func Load(factory *api.ProviderFactory, opts Options) ([]api.Notification, map[string]error) {
+	errs := make(map[string]error)
+
+	state, err := LoadState()
+	if err != nil {
+		errs["inbox"] = err
+		state = &State{Read: map[string]string{}, Done: map[string]string{}}
+	}
+
+	providers := opts.Providers
+	if len(providers) == 0 {
+		providers = factory.ConfiguredProviders()
+	}
+
+	var result []api.Notification
+	for _, name := range providers {
+		source, err := getSource(factory, name)
+		if err != nil {
+			errs[name] = err
+			continue
+		}
+
+		notifications, err := source.ListNotifications(api.NotificationOptions{All: opts.All, Repos: opts.Repos[name]})
+		if err != nil {
+			errs[name] = err
+			continue
+		}
+
+		for _, n := range notifications {
+			if state.apply(&n) && !opts.All {
+				continue
+			}
+			if !opts.All && !n.Unread {
+				continue
+			}
+			if !matchesReason(n.Reason, opts.Reasons) {
+				continue
+			}
+			result = append(result, n)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		ti, _ := time.Parse(time.RFC3339, result[i].UpdatedAt)
+		tj, _ := time.Parse(time.RFC3339, result[j].UpdatedAt)
+		return ti.After(tj)
+	})
+
+	return result, errs
+}
+
+func matchesReason(reason string, reasons []string) bool {
+	if len(reasons) == 0 {
+		return true
+	}
+	for _, r := range reasons {
+		if strings.EqualFold(r, reason) {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkRead marks the notification identified by key (provider:id) as read
+func MarkRead(factory *api.ProviderFactory, key string) error {
+	return mark(factory, key, false)
+}
+
+// MarkDone marks the notification identified by key (provider:id) as done
+func MarkDone(factory *api.ProviderFactory, key string) error {
+	return mark(factory, key, true)
+}
+
+func mark(factory *api.ProviderFactory, key string, done bool) error {
+	name, id, ok := strings.Cut(key, ":")
+	if !ok || id == "" {
+		return fmt.Errorf("invalid notification id '%s' (expected provider:id)", key)
+	}
+
+	source, err := getSource(factory, name)
+	if err != nil {
+		return err
+	}
+
+	if done {
+		err = source.MarkNotificationDone(id)
+	} else {
+		err = source.MarkNotificationRead(id)
+	}
+	if !errors.Is(err, api.ErrNotSupported) {
+		return err
+	}
+
+	// Fall back to local state for providers without server-side marks
+	state, err := LoadState()
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	if done {
+		state.Done[key] = now
+	} else {
+		state.Read[key] = now
+	}
+	return state.Save()
+}
+
+func getSource(factory *api.ProviderFactory, name string) (api.NotificationSource, error) {
+	provider, err := factory.GetProvider(name)
+	if err != nil {
+		return nil, err
+	}
+	source, ok := provider.(api.NotificationSource)
+	if !ok {
+		return nil, fmt.Errorf("notifications are not supported for %s", name)
+	}
+	return source, nil
+}
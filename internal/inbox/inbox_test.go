@@ -0,0 +1,78 @@
+package inbox
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gitkraken/gk-cli/internal/api"
+)
+
+func TestMarkDoneFallsBackToLocalState(t *testing.T) {
+	statePath = filepath.Join(t.TempDir(), "inbox.json")
+	defer func() { statePath = "" }()
+
+	factory := api.NewProviderFactory()
+	factory.SetBitbucketCreds("user", "pass")
+
+	if err := MarkDone(factory, "bitbucket:acme/api#1"); err != nil {
+		t.Fatalf("Failed to mark done: %v", err)
+	}
+
+	state, err := LoadState()
+	if err != nil {
+		t.Fatalf("Failed to load state: %v", err)
+	}
+
+	old := api.Notification{
+		Provider:  "bitbucket",
+		ID:        "acme/api#1",
+		UpdatedAt: time.Now().Add(-time.Hour).Format(time.RFC3339),
+		Unread:    true,
+	}
+	if !state.apply(&old) {
+		t.Errorf("Expected notification marked done to be hidden")
+	}
+
+	updated := old
+	updated.UpdatedAt = time.Now().Add(time.Hour).Format(time.RFC3339)
+	if state.apply(&updated) {
+		t.Errorf("Expected notification with newer activity to be shown again")
+	}
+}
+
+func TestMarkRejectsInvalidID(t *testing.T) {
+	if err := MarkRead(api.NewProviderFactory(), "12345"); err == nil {
+		t.Errorf("Expected error for id without provider prefix")
+	}
+}
+
+func TestMarkReadKeepsGitLabTodosPending(t *testing.T) {
+	statePath = filepath.Join(t.TempDir(), "inbox.json")
+	defer func() { statePath = "" }()
+
+	factory := api.NewProviderFactory()
+	factory.SetGitLabToken("token")
+
+	// Reading must not complete the to-do on GitLab, so it is kept locally
+	if err := MarkRead(factory, "gitlab:42"); err != nil {
+		t.Fatalf("Failed to mark read: %v", err)
+	}
+
+	state, err := LoadState()
+	if err != nil {
+		t.Fatalf("Failed to load state: %v", err)
+	}
+	if state.Read["gitlab:42"] == "" {
+		t.Errorf("Expected the read mark to be stored locally")
+	}
+
+	info, err := os.Stat(statePath)
+	if err != nil {
+		t.Fatalf("Failed to stat state: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("Expected state file mode 0600, got %o", perm)
+	}
+}
@@ -1,14 +1,12 @@
 package launchpad
 
 import (
-	"context"
 	"fmt"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/gitkraken/gk-cli/internal/api"
-	"github.com/gitkraken/gk-cli/internal/config"
 	"github.com/gitkraken/gk-cli/internal/workspace"
 )
 
@@ -22,6 +20,8 @@ type Item struct {
 	State       string
 	Author      string
 	URL         string
+	ReviewState string
+	CheckStatus string
 	CreatedAt   string
 	UpdatedAt   string
 	Pinned      bool
@@ -34,83 +34,47 @@ type Launchpad struct {
 }
 
 // LoadItems loads PRs and Issues from workspace repositories
-func LoadItems(ws *workspace.Workspace) (*Launchpad, error) {
-	factory := api.NewProviderFactory()
-	cfg := config.Get()
-
-	// Setup providers from config
-	if providers, ok := cfg.Providers["github"].(map[string]interface{}); ok {
-		if token, ok := providers["token"].(string); ok {
-			factory.SetGitHubToken(token)
-		}
-	}
-	if providers, ok := cfg.Providers["gitlab"].(map[string]interface{}); ok {
-		if token, ok := providers["token"].(string); ok {
-			factory.SetGitLabToken(token)
-		}
-	}
-	if providers, ok := cfg.Providers["bitbucket"].(map[string]interface{}); ok {
-		if user, ok := providers["username"].(string); ok {
-			if pass, ok := providers["password"].(string); ok {
-				factory.SetBitbucketCreds(user, pass)
-			}
+func LoadItems(factory *api.ProviderFactory, ws *workspace.Workspace) (*Launchpad, error) {
+	var remotes []string
+	for _, repo := range ws.Repos {
+		if repo.Remote != "" {
+			remotes = append(remotes, repo.Remote)
 		}
 	}
 
 	var items []Item
-	ctx := context.Background()
-
-	for _, repo := range ws.Repos {
-		if repo.Remote == "" {
-			continue
+	results := factory.FetchRemotes(remotes, api.BatchOptions{State: "open", PullRequests: true, Issues: true})
+	for _, result := range results {
+		for _, pr := range result.Items.PullRequests {
+			items = append(items, Item{
+				Type:        "pr",
+				Provider:    result.Provider,
+				Repo:        result.Repo.String(),
+				Number:      pr.Number,
+				Title:       pr.Title,
+				State:       pr.State,
+				Author:      pr.Author,
+				URL:         pr.URL,
+				ReviewState: pr.ReviewState,
+				CheckStatus: pr.CheckStatus,
+				CreatedAt:   pr.CreatedAt,
+				UpdatedAt:   pr.UpdatedAt,
+			})
 		}
 
-		providerName, owner, repoName, err := api.ParseRepoURL(repo.Remote)
-		if err != nil {
-			continue
-		}
-
-		provider, err := factory.GetProvider(providerName)
-		if err != nil {
-			continue
-		}
-
-		// Get PRs
-		prs, err := provider.ListPullRequests(owner, repoName, "open")
-		if err == nil {
-			for _, pr := range prs {
-				items = append(items, Item{
-					Type:      "pr",
-					Provider:  providerName,
-					Repo:      fmt.Sprintf("%s/%s", owner, repoName),
-					Number:    pr.Number,
-					Title:     pr.Title,
-					State:     pr.State,
-					Author:    pr.Author,
-					URL:       pr.URL,
-					CreatedAt: pr.CreatedAt,
-					UpdatedAt: pr.UpdatedAt,
-				})
-			}
-		}
-
-		// Get Issues
-		issues, err := provider.ListIssues(owner, repoName, "open")
-		if err == nil {
-			for _, issue := range issues {
-				items = append(items, Item{
-					Type:      "issue",
-					Provider:  providerName,
-					Repo:      fmt.Sprintf("%s/%s", owner, repoName),
-					Number:    issue.Number,
-					Title:     issue.Title,
-					State:     issue.State,
-					Author:    issue.Author,
-					URL:       issue.URL,
-					CreatedAt: issue.CreatedAt,
-					UpdatedAt: issue.UpdatedAt,
-				})
-			}
+		for _, issue := range result.Items.Issues {
+			items = append(items, Item{
+				Type:      "issue",
+				Provider:  result.Provider,
+				Repo:      result.Repo.String(),
+				Number:    issue.Number,
+				Title:     issue.Title,
+				State:     issue.State,
+				Author:    issue.Author,
+				URL:       issue.URL,
+				CreatedAt: issue.CreatedAt,
+				UpdatedAt: issue.UpdatedAt,
+			})
 		}
 	}
 
@@ -178,6 +142,9 @@ func (l *Launchpad) displayItem(index int, item Item, pinned bool) {
 		pinIcon = "📌 "
 	}
 
-	fmt.Printf("%s%d. %s%s #%d: %s\n", pinIcon, index, icon, item.Number, item.Title)
+	fmt.Printf("%s%d. %s #%d: %s\n", pinIcon, index, icon, item.Number, item.Title)
 	fmt.Printf("   %s/%s | %s | %s\n", item.Provider, item.Repo, item.Author, item.URL)
+	if item.ReviewState != "" || item.CheckStatus != "" {
+		fmt.Printf("   review: %s | checks: %s\n", item.ReviewState, item.CheckStatus)
+	}
 }
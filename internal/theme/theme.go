@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/gitkraken/gk-cli/pkg/utils"
 )
 
 // Theme represents a color theme
@@ -52,14 +54,11 @@ func LoadTheme(themePath string) (*Theme, error) {
 // GetThemePath returns the path to a theme file
 func GetThemePath(themeName string) (string, error) {
 	// Try multiple locations for theme files
-	possiblePaths := []string{
-		filepath.Join("/usr/local/share/gk/themes", themeName+".json"),
-		filepath.Join(os.Getenv("HOME"), ".config/gk/themes", themeName+".json"),
-		filepath.Join(os.Getenv("HOME"), ".gk/themes", themeName+".json"),
-		filepath.Join(".", "themes", themeName+".json"),
-	}
+	possibleDirs := []string{"/usr/local/share/gk/themes"}
+	possibleDirs = append(possibleDirs, themeDirs()...)
 
-	for _, path := range possiblePaths {
+	for _, dir := range possibleDirs {
+		path := filepath.Join(dir, themeName+".json")
 		if _, err := os.Stat(path); err == nil {
 			return path, nil
 		}
@@ -70,16 +69,10 @@ func GetThemePath(themeName string) (string, error) {
 
 // ListThemes lists all available themes
 func ListThemes() ([]string, error) {
-	themeDirs := []string{
-		filepath.Join(os.Getenv("HOME"), ".config/gk/themes"),
-		filepath.Join(os.Getenv("HOME"), ".gk/themes"),
-		filepath.Join(".", "themes"),
-	}
-
 	var themes []string
 	seen := make(map[string]bool)
 
-	for _, dir := range themeDirs {
+	for _, dir := range themeDirs() {
 		entries, err := os.ReadDir(dir)
 		if err != nil {
 			continue
@@ -98,3 +91,12 @@ func ListThemes() ([]string, error) {
 
 	return themes, nil
 }
+
+// themeDirs returns the directories custom themes are read from
+func themeDirs() []string {
+	var dirs []string
+	if dir, err := utils.GetThemesDir(); err == nil {
+		dirs = append(dirs, dir)
+	}
+	return append(dirs, filepath.Join(".", "themes"))
+}
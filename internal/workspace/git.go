@@ -8,51 +8,39 @@ import (
 	"strings"
 )
 
-// GitOperation performs a git operation on all repos in a workspace
-func GitOperation(ws *Workspace, operation string, args []string) error {
-	repos := ws.GetRepoPaths()
-	if len(repos) == 0 {
-		return fmt.Errorf("no repositories in workspace '%s'", ws.Name)
+// GitOperation performs a git operation on all repos in a workspace. Unless
+// it runs one repository at a time, git and ssh are kept from prompting for
+// credentials, which would block on a terminal shared by several repositories;
+// they must come from a credential helper or an ssh agent.
+func GitOperation(ws *Workspace, operation string, args []string, opts RunOptions) ([]Result, error) {
+	opts.check = checkGitRepo
+	if opts.Jobs != 1 {
+		opts.env = gitBatchEnv()
 	}
+	return Run(ws, "git", append([]string{operation}, args...), opts)
+}
 
-	var errors []string
-	for _, repoPath := range repos {
-		if err := runGitOperation(repoPath, operation, args); err != nil {
-			errors = append(errors, fmt.Sprintf("%s: %v", repoPath, err))
-			continue
-		}
-		fmt.Printf("✓ %s\n", repoPath)
+// gitBatchEnv is the environment that makes git fail instead of prompting
+func gitBatchEnv() []string {
+	env := []string{"GIT_TERMINAL_PROMPT=0"}
+	// GIT_SSH_COMMAND would override a GIT_SSH program
+	if os.Getenv("GIT_SSH") != "" && os.Getenv("GIT_SSH_COMMAND") == "" {
+		return env
 	}
-
-	if len(errors) > 0 {
-		return fmt.Errorf("errors occurred:\n%s", strings.Join(errors, "\n"))
+	ssh := os.Getenv("GIT_SSH_COMMAND")
+	if ssh == "" {
+		ssh = "ssh"
 	}
-
-	return nil
+	return append(env, "GIT_SSH_COMMAND="+ssh+" -o BatchMode=yes")
 }
 
-// runGitOperation runs a git command in a repository directory
-func runGitOperation(repoPath, operation string, args []string) error {
-	// Verify the path exists and is a git repository
-	if _, err := os.Stat(repoPath); os.IsNotExist(err) {
-		return fmt.Errorf("repository path does not exist: %s", repoPath)
-	}
-
+// checkGitRepo verifies that a path is a git repository
+func checkGitRepo(repoPath string) error {
 	gitDir := filepath.Join(repoPath, ".git")
 	if _, err := os.Stat(gitDir); os.IsNotExist(err) {
 		return fmt.Errorf("not a git repository: %s", repoPath)
 	}
-
-	// Build git command
-	gitArgs := []string{operation}
-	gitArgs = append(gitArgs, args...)
-
-	cmd := exec.Command("git", gitArgs...)
-	cmd.Dir = repoPath
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-
-	return cmd.Run()
+	return nil
 }
 
 // DetectRepo detects repository information from a path
@@ -115,7 +103,6 @@ func LocateRepos(rootDir string) ([]*Repo, error) {
 		}
 
 		// Check if this directory is a git repository
-		gitDir := filepath.Join(path, ".git")
 		if info.IsDir() && info.Name() == ".git" {
 			// Parent directory is a git repo
 			repoPath := filepath.Dir(path)
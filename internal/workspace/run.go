@@ -0,0 +1,136 @@
+package workspace
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Result is the outcome of running a command in one repository
+type Result struct {
+	Repo     Repo
+	Output   []byte // stdout and stderr, interleaved as written
+	Err      error
+	Duration time.Duration
+	Skipped  bool // not run because an earlier repository failed
+}
+
+// RunOptions control how a command is run across a workspace
+type RunOptions struct {
+	// Jobs is how many repositories run at once; 0 means one per CPU
+	Jobs int
+	// FailFast stops starting repositories once one has failed; those
+	// already running are left to finish
+	FailFast bool
+	// OnDone is called as each repository finishes, one call at a time
+	OnDone func(Result)
+
+	// check is run before the command and fails the repository if it errors
+	check func(repoPath string) error
+	// env is added to the command's environment
+	env []string
+}
+
+// Run runs a command in every repository of a workspace that has a local
+// path, with its output captured per repository. The command gets the
+// repository's name and remote in GK_REPO_NAME and GK_REPO_REMOTE. Results
+// are returned in workspace order.
+func Run(ws *Workspace, name string, args []string, opts RunOptions) ([]Result, error) {
+	var repos []Repo
+	for _, repo := range ws.Repos {
+		if repo.Path != "" {
+			repos = append(repos, repo)
+		}
+	}
+	if len(repos) == 0 {
+		return nil, fmt.Errorf("no repositories in workspace '%s'", ws.Name)
+	}
+
+	jobs := opts.Jobs
+	if jobs <= 0 {
+		jobs = runtime.NumCPU()
+	}
+
+	results := make([]Result, len(repos))
+	slots := make(chan struct{}, jobs)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	failed := false
+
+	for i, repo := range repos {
+		slots <- struct{}{}
+		mu.Lock()
+		stop := opts.FailFast && failed
+		mu.Unlock()
+		if stop {
+			<-slots
+			results[i] = Result{Repo: repo, Skipped: true}
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, repo Repo) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			result := runInRepo(repo, name, args, opts)
+			results[i] = result
+			mu.Lock()
+			defer mu.Unlock()
+			if result.Err != nil {
+				failed = true
+			}
+			if opts.OnDone != nil {
+				opts.OnDone(result)
+			}
+		}(i, repo)
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+// runInRepo runs a command in a repository directory and captures its output
+func runInRepo(repo Repo, name string, args []string, opts RunOptions) Result {
+	start := time.Now()
+	result := Result{Repo: repo}
+
+	if _, err := os.Stat(repo.Path); os.IsNotExist(err) {
+		result.Err = fmt.Errorf("repository path does not exist: %s", repo.Path)
+		return result
+	}
+	if opts.check != nil {
+		if err := opts.check(repo.Path); err != nil {
+			result.Err = err
+			return result
+		}
+	}
+
+	var output bytes.Buffer
+	cmd := exec.Command(name, args...)
+	cmd.Dir = repo.Path
+	cmd.Env = append(os.Environ(), "GK_REPO_NAME="+repo.Name, "GK_REPO_REMOTE="+repo.Remote)
+	cmd.Env = append(cmd.Env, opts.env...)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	result.Err = cmd.Run()
+	result.Output = output.Bytes()
+	result.Duration = time.Since(start)
+	return result
+}
+
+// Failed returns the results that failed; skipped repositories don't count
+func Failed(results []Result) []Result {
+	var failed []Result
+	for _, r := range results {
+		if r.Err != nil {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
@@ -0,0 +1,99 @@
+package workspace
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGitOperationRunsEveryRepo(t *testing.T) {
+	dir := t.TempDir()
+	ws := &Workspace{Name: "test"}
+	for _, name := range []string{"a", "b", "c"} {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(path, 0755)
+		if name != "b" {
+			if err := exec.Command("git", "init", "-q", path).Run(); err != nil {
+				t.Skipf("git not available: %v", err)
+			}
+		}
+		ws.Repos = append(ws.Repos, Repo{Name: name, Path: path})
+	}
+
+	done := 0
+	results, err := GitOperation(ws, "status", []string{"--short", "--branch"}, RunOptions{
+		Jobs:   2,
+		OnDone: func(Result) { done++ },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if done != 3 || len(results) != 3 {
+		t.Fatalf("got %d callbacks and %d results, want 3", done, len(results))
+	}
+	for i, name := range []string{"a", "b", "c"} {
+		if results[i].Repo.Name != name {
+			t.Errorf("results[%d] = %s, want %s", i, results[i].Repo.Name, name)
+		}
+	}
+	if !strings.Contains(string(results[0].Output), "##") {
+		t.Errorf("expected captured output, got %q", results[0].Output)
+	}
+	if failed := Failed(results); len(failed) != 1 || failed[0].Repo.Name != "b" {
+		t.Errorf("expected only b to fail, got %v", failed)
+	}
+}
+
+func TestRunFailFastAndEnv(t *testing.T) {
+	dir := t.TempDir()
+	ws := &Workspace{Name: "test"}
+	for _, name := range []string{"a", "b", "c"} {
+		os.MkdirAll(filepath.Join(dir, name), 0755)
+		ws.Repos = append(ws.Repos, Repo{Name: name, Path: filepath.Join(dir, name), Remote: "https://example.com/" + name})
+	}
+
+	script := `echo "$GK_REPO_NAME $GK_REPO_REMOTE"; test "$GK_REPO_NAME" != a`
+	results, err := Run(ws, "sh", []string{"-c", script}, RunOptions{Jobs: 1, FailFast: true})
+	if err != nil {
+		t.Skipf("sh not available: %v", err)
+	}
+	if got := strings.TrimSpace(string(results[0].Output)); got != "a https://example.com/a" {
+		t.Errorf("output = %q", got)
+	}
+	if results[0].Err == nil || !results[1].Skipped || !results[2].Skipped {
+		t.Errorf("expected a to fail and the rest to be skipped: %+v", results)
+	}
+	if len(Failed(results)) != 1 {
+		t.Errorf("expected skipped repositories not to count as failed")
+	}
+}
+
+func TestGitOperationDisablesPromptsWhenParallel(t *testing.T) {
+	t.Setenv("GIT_SSH", "")
+	t.Setenv("GIT_SSH_COMMAND", "")
+	path := t.TempDir()
+	if err := exec.Command("git", "init", "-q", path).Run(); err != nil {
+		t.Skipf("git not available: %v", err)
+	}
+	ws := &Workspace{Name: "test", Repos: []Repo{{Name: "a", Path: path}}}
+
+	// An alias shows the environment git runs with
+	args := []string{"alias.prompts=!echo \"[$GIT_TERMINAL_PROMPT] [$GIT_SSH_COMMAND]\"", "prompts"}
+	for jobs, want := range map[int]string{
+		2: "[0] [ssh -o BatchMode=yes]",
+		1: "[] []",
+	} {
+		results, err := GitOperation(ws, "-c", args, RunOptions{Jobs: jobs})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if results[0].Err != nil {
+			t.Fatalf("jobs %d: %v\n%s", jobs, results[0].Err, results[0].Output)
+		}
+		if got := strings.TrimSpace(string(results[0].Output)); got != want {
+			t.Errorf("jobs %d: got %q, want %q", jobs, got, want)
+		}
+	}
+}
@@ -6,6 +6,9 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/gitkraken/gk-cli/internal/filelock"
+	"github.com/gitkraken/gk-cli/pkg/utils"
 )
 
 // Workspace represents a GitKraken workspace
@@ -29,14 +32,22 @@ var (
 	workspacesDir string
 )
 
+// SetDir sets the directory workspaces are stored in, e.g. the one of the
+// active profile
+func SetDir(dir string) {
+	workspacesDir = dir
+}
+
 // Init initializes the workspace system
 func Init() error {
-	home, err := os.UserHomeDir()
-	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+	if workspacesDir == "" {
+		dir, err := utils.GetConfigDir()
+		if err != nil {
+			return fmt.Errorf("failed to get config directory: %w", err)
+		}
+		workspacesDir = filepath.Join(dir, "workspaces")
 	}
 
-	workspacesDir = filepath.Join(home, ".config", "gk", "workspaces")
 	if err := os.MkdirAll(workspacesDir, 0755); err != nil {
 		return fmt.Errorf("failed to create workspaces directory: %w", err)
 	}
@@ -122,7 +133,7 @@ func (ws *Workspace) Save() error {
 		return fmt.Errorf("failed to marshal workspace: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	if err := filelock.WriteFile(path, data, 0600); err != nil {
 		return fmt.Errorf("failed to write workspace file: %w", err)
 	}
 
@@ -139,33 +150,72 @@ func Delete(name string) error {
 	if err := os.Remove(path); err != nil {
 		return fmt.Errorf("failed to delete workspace: %w", err)
 	}
+	os.Remove(path + ".lock")
 
 	return nil
 }
 
 // AddRepo adds a repository to the workspace
 func (ws *Workspace) AddRepo(repo Repo) error {
-	// Check if repo already exists
-	for i, r := range ws.Repos {
-		if r.Name == repo.Name || r.Path == repo.Path || r.Remote == repo.Remote {
-			ws.Repos[i] = repo // Update existing
-			return ws.Save()
+	return ws.update(func(saved *Workspace) error {
+		// Check if repo already exists
+		for i, r := range saved.Repos {
+			if r.Name == repo.Name || (r.Path != "" && r.Path == repo.Path) || (r.Remote != "" && r.Remote == repo.Remote) {
+				saved.Repos[i] = repo // Update existing
+				return nil
+			}
 		}
-	}
-
-	ws.Repos = append(ws.Repos, repo)
-	return ws.Save()
+		saved.Repos = append(saved.Repos, repo)
+		return nil
+	})
 }
 
 // RemoveRepo removes a repository from the workspace
 func (ws *Workspace) RemoveRepo(name string) error {
-	for i, r := range ws.Repos {
-		if r.Name == name {
-			ws.Repos = append(ws.Repos[:i], ws.Repos[i+1:]...)
-			return ws.Save()
+	return ws.update(func(saved *Workspace) error {
+		for i, r := range saved.Repos {
+			if r.Name == name {
+				saved.Repos = append(saved.Repos[:i], saved.Repos[i+1:]...)
+				return nil
+			}
 		}
+		return fmt.Errorf("repository '%s' not found in workspace", name)
+	})
+}
+
+// update applies change to the workspace file as it is on disk and saves
+// it, holding its lock throughout so repositories other gk processes added
+// meanwhile are kept. ws is replaced with the result.
+func (ws *Workspace) update(change func(saved *Workspace) error) error {
+	if err := Init(); err != nil {
+		return err
 	}
-	return fmt.Errorf("repository '%s' not found in workspace", name)
+
+	path := filepath.Join(workspacesDir, ws.Name+".json")
+	var updated Workspace
+	err := filelock.Update(path, 0600, func(data []byte) ([]byte, error) {
+		updated = *ws
+		updated.Repos = append([]Repo(nil), ws.Repos...)
+		if len(data) > 0 {
+			updated = Workspace{}
+			if err := json.Unmarshal(data, &updated); err != nil {
+				return nil, fmt.Errorf("failed to parse workspace file: %w", err)
+			}
+		}
+		if err := change(&updated); err != nil {
+			return nil, err
+		}
+		data, err := json.MarshalIndent(&updated, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal workspace: %w", err)
+		}
+		return data, nil
+	})
+	if err != nil {
+		return err
+	}
+	*ws = updated
+	return nil
 }
 
 // GetRepoPaths returns all repository paths in the workspace
@@ -178,3 +228,30 @@ func (ws *Workspace) GetRepoPaths() []string {
 	}
 	return paths
 }
+
+// FindByPath returns the first workspace, by name, with a repository that
+// contains dir, or nil if there is none
+func FindByPath(dir string) (*Workspace, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	names, err := List()
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		ws, err := Load(name)
+		if err != nil {
+			continue
+		}
+		for _, path := range ws.GetRepoPaths() {
+			rel, err := filepath.Rel(path, dir)
+			if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				return ws, nil
+			}
+		}
+	}
+	return nil, nil
+}
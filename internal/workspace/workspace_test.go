@@ -87,3 +87,49 @@ func TestListWorkspaces(t *testing.T) {
 		t.Errorf("Expected 2 workspaces, got %d", len(workspaces))
 	}
 }
+
+func TestFindByPath(t *testing.T) {
+	testDir := t.TempDir()
+	workspacesDir = filepath.Join(testDir, "workspaces")
+	defer func() { workspacesDir = "" }()
+
+	repoPath := filepath.Join(testDir, "app")
+	ws, _ := Create("backend", "local", "")
+	ws.AddRepo(Repo{Name: "app", Path: repoPath})
+	ws.Save()
+	Create("frontend", "local", "")
+
+	found, err := FindByPath(filepath.Join(repoPath, "cmd"))
+	if err != nil || found == nil || found.Name != "backend" {
+		t.Errorf("FindByPath = %v, %v; want backend", found, err)
+	}
+	if found, _ := FindByPath(repoPath + "-other"); found != nil {
+		t.Errorf("expected no workspace for a sibling directory, got %s", found.Name)
+	}
+}
+
+func TestAddRepoKeepsReposAddedElsewhere(t *testing.T) {
+	workspacesDir = filepath.Join(t.TempDir(), "workspaces")
+	defer func() { workspacesDir = "" }()
+
+	if _, err := Create("backend", "local", ""); err != nil {
+		t.Fatal(err)
+	}
+	// Two processes that loaded the workspace before either added a repo
+	first, _ := Load("backend")
+	second, _ := Load("backend")
+	if err := first.AddRepo(Repo{Name: "api", Path: "/src/api"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := second.AddRepo(Repo{Name: "web", Path: "/src/web"}); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := Load("backend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saved.Repos) != 2 || len(second.Repos) != 2 {
+		t.Errorf("expected both repos to be kept, got %v", saved.Repos)
+	}
+}
@@ -7,6 +7,20 @@ import (
 	"strings"
 )
 
+// IsTerminal reports whether stdin is an interactive terminal, i.e. whether
+// a prompt can be answered
+func IsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	// The null device is a character device too
+	if null, err := os.Stat(os.DevNull); err == nil && os.SameFile(info, null) {
+		return false
+	}
+	return true
+}
+
 // PromptString prompts the user for input and returns the trimmed string
 func PromptString(prompt string) (string, error) {
 	fmt.Print(prompt)
@@ -20,12 +34,12 @@ func PromptString(prompt string) (string, error) {
 
 // PromptYesNo prompts the user for yes/no input
 func PromptYesNo(prompt string, defaultYes bool) (bool, error) {
-	defaultStr := "n"
+	hint := "y/N"
 	if defaultYes {
-		defaultStr = "y"
+		hint = "Y/n"
 	}
 
-	fmt.Printf("%s [y/N]: ", prompt)
+	fmt.Printf("%s [%s]: ", prompt, hint)
 	reader := bufio.NewReader(os.Stdin)
 	input, err := reader.ReadString('\n')
 	if err != nil {
@@ -0,0 +1,469 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// FilterJSON applies a jq-like filter expression to decoded JSON data and
+// returns the resulting values. Supported syntax:
+//
+//	.                identity
+//	.foo.bar         object fields (also .["foo bar"])
+//	.[0] .[-1]       array indexes
+//	.[] .foo[]       iterate over array elements or object values
+//	a | b            pipe results of a into b
+//	length, keys     length of a string, array or object; sorted object keys
+//	select(cond)     keep values where cond is true, e.g. select(.state == "open")
+func FilterJSON(expr string, data interface{}) ([]interface{}, error) {
+	p := &filterParser{src: expr}
+	f, err := p.parsePipeline()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return nil, p.errorf("unexpected %q", p.src[p.pos:])
+	}
+	return f([]interface{}{data})
+}
+
+type filterFunc func([]interface{}) ([]interface{}, error)
+
+type filterParser struct {
+	src string
+	pos int
+}
+
+func (p *filterParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid filter at position %d: %s", p.pos+1, fmt.Sprintf(format, args...))
+}
+
+func (p *filterParser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *filterParser) peek(s string) bool {
+	p.skipSpace()
+	return strings.HasPrefix(p.src[p.pos:], s)
+}
+
+// parsePipeline parses terms separated by '|'
+func (p *filterParser) parsePipeline() (filterFunc, error) {
+	var stages []filterFunc
+	for {
+		stage, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		stages = append(stages, stage)
+		if !p.peek("|") {
+			break
+		}
+		p.pos++
+	}
+	return func(in []interface{}) ([]interface{}, error) {
+		var err error
+		for _, stage := range stages {
+			if in, err = stage(in); err != nil {
+				return nil, err
+			}
+		}
+		return in, nil
+	}, nil
+}
+
+func (p *filterParser) parseTerm() (filterFunc, error) {
+	p.skipSpace()
+	switch {
+	case p.peek("."):
+		return p.parsePath()
+	case p.peek("length"):
+		p.pos += len("length")
+		return eachValue(filterLength), nil
+	case p.peek("keys"):
+		p.pos += len("keys")
+		return eachValue(filterKeys), nil
+	case p.peek("select("):
+		p.pos += len("select(")
+		cond, err := p.parseCondition()
+		if err != nil {
+			return nil, err
+		}
+		if !p.peek(")") {
+			return nil, p.errorf("expected ')'")
+		}
+		p.pos++
+		return func(in []interface{}) ([]interface{}, error) {
+			var out []interface{}
+			for _, v := range in {
+				ok, err := cond(v)
+				if err != nil {
+					return nil, err
+				}
+				if ok {
+					out = append(out, v)
+				}
+			}
+			return out, nil
+		}, nil
+	case p.pos >= len(p.src):
+		return nil, p.errorf("unexpected end of filter")
+	default:
+		return nil, p.errorf("unexpected %q", p.src[p.pos:])
+	}
+}
+
+// parsePath parses '.' followed by field, index and iteration segments
+func (p *filterParser) parsePath() (filterFunc, error) {
+	var segments []filterFunc
+	p.pos++ // leading '.'
+	if p.pos < len(p.src) && isIdentStart(p.src[p.pos]) {
+		segments = append(segments, fieldSegment(p.parseIdent()))
+	}
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case '.':
+			p.pos++
+			if p.pos < len(p.src) && p.src[p.pos] == '[' {
+				continue
+			}
+			if p.pos >= len(p.src) || !isIdentStart(p.src[p.pos]) {
+				return nil, p.errorf("expected field name after '.'")
+			}
+			segments = append(segments, fieldSegment(p.parseIdent()))
+		case '[':
+			seg, err := p.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, seg)
+		default:
+			return chain(segments), nil
+		}
+	}
+	return chain(segments), nil
+}
+
+func (p *filterParser) parseBracket() (filterFunc, error) {
+	p.pos++ // '['
+	p.skipSpace()
+	if p.peek("]") {
+		p.pos++
+		return iterate, nil
+	}
+	if p.peek(`"`) {
+		key, err := p.parseString()
+		if err != nil {
+			return nil, err
+		}
+		if !p.peek("]") {
+			return nil, p.errorf("expected ']'")
+		}
+		p.pos++
+		return fieldSegment(key), nil
+	}
+
+	start := p.pos
+	if p.pos < len(p.src) && p.src[p.pos] == '-' {
+		p.pos++
+	}
+	for p.pos < len(p.src) && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+		p.pos++
+	}
+	index, err := strconv.Atoi(p.src[start:p.pos])
+	if err != nil {
+		return nil, p.errorf("expected index, string or ']'")
+	}
+	if !p.peek("]") {
+		return nil, p.errorf("expected ']'")
+	}
+	p.pos++
+	return indexSegment(index), nil
+}
+
+func (p *filterParser) parseIdent() string {
+	start := p.pos
+	for p.pos < len(p.src) && (isIdentStart(p.src[p.pos]) || (p.src[p.pos] >= '0' && p.src[p.pos] <= '9')) {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *filterParser) parseString() (string, error) {
+	start := p.pos
+	p.pos++ // opening quote
+	for p.pos < len(p.src) && p.src[p.pos] != '"' {
+		if p.src[p.pos] == '\\' {
+			p.pos++
+		}
+		p.pos++
+	}
+	if p.pos >= len(p.src) {
+		return "", p.errorf("unterminated string")
+	}
+	p.pos++
+	s, err := strconv.Unquote(p.src[start:p.pos])
+	if err != nil {
+		return "", p.errorf("invalid string %s", p.src[start:p.pos])
+	}
+	return s, nil
+}
+
+// parseCondition parses `pipeline [op literal]` inside select()
+func (p *filterParser) parseCondition() (func(interface{}) (bool, error), error) {
+	left, err := p.parsePipeline()
+	if err != nil {
+		return nil, err
+	}
+
+	op := ""
+	for _, candidate := range []string{"==", "!=", ">=", "<=", ">", "<"} {
+		if p.peek(candidate) {
+			op = candidate
+			p.pos += len(candidate)
+			break
+		}
+	}
+
+	var right interface{}
+	if op != "" {
+		if right, err = p.parseLiteral(); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(v interface{}) (bool, error) {
+		values, err := left([]interface{}{v})
+		if err != nil {
+			return false, err
+		}
+		for _, value := range values {
+			if op == "" && truthy(value) {
+				return true, nil
+			}
+			if op != "" && compare(value, op, right) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}, nil
+}
+
+func (p *filterParser) parseLiteral() (interface{}, error) {
+	p.skipSpace()
+	if p.peek(`"`) {
+		return p.parseString()
+	}
+	for _, word := range []string{"true", "false", "null"} {
+		if p.peek(word) {
+			p.pos += len(word)
+			switch word {
+			case "true":
+				return true, nil
+			case "false":
+				return false, nil
+			}
+			return nil, nil
+		}
+	}
+	start := p.pos
+	for p.pos < len(p.src) && strings.ContainsRune("-+.eE0123456789", rune(p.src[p.pos])) {
+		p.pos++
+	}
+	n, err := strconv.ParseFloat(p.src[start:p.pos], 64)
+	if err != nil {
+		return nil, p.errorf("expected string, number, true, false or null")
+	}
+	return n, nil
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func chain(segments []filterFunc) filterFunc {
+	return func(in []interface{}) ([]interface{}, error) {
+		var err error
+		for _, seg := range segments {
+			if in, err = seg(in); err != nil {
+				return nil, err
+			}
+		}
+		return in, nil
+	}
+}
+
+// eachValue lifts a single-value function over a stream of values
+func eachValue(fn func(interface{}) (interface{}, error)) filterFunc {
+	return func(in []interface{}) ([]interface{}, error) {
+		out := make([]interface{}, 0, len(in))
+		for _, v := range in {
+			r, err := fn(v)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, r)
+		}
+		return out, nil
+	}
+}
+
+func fieldSegment(name string) filterFunc {
+	return eachValue(func(v interface{}) (interface{}, error) {
+		switch obj := v.(type) {
+		case map[string]interface{}:
+			return obj[name], nil
+		case nil:
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("cannot index %s with %q", typeName(v), name)
+		}
+	})
+}
+
+func indexSegment(index int) filterFunc {
+	return eachValue(func(v interface{}) (interface{}, error) {
+		switch arr := v.(type) {
+		case []interface{}:
+			i := index
+			if i < 0 {
+				i += len(arr)
+			}
+			if i < 0 || i >= len(arr) {
+				return nil, nil
+			}
+			return arr[i], nil
+		case nil:
+			return nil, nil
+		default:
+			return nil, fmt.Errorf("cannot index %s with a number", typeName(v))
+		}
+	})
+}
+
+func iterate(in []interface{}) ([]interface{}, error) {
+	var out []interface{}
+	for _, v := range in {
+		switch c := v.(type) {
+		case []interface{}:
+			out = append(out, c...)
+		case map[string]interface{}:
+			keys := sortedKeys(c)
+			for _, k := range keys {
+				out = append(out, c[k])
+			}
+		default:
+			return nil, fmt.Errorf("cannot iterate over %s", typeName(v))
+		}
+	}
+	return out, nil
+}
+
+func filterLength(v interface{}) (interface{}, error) {
+	switch c := v.(type) {
+	case []interface{}:
+		return float64(len(c)), nil
+	case map[string]interface{}:
+		return float64(len(c)), nil
+	case string:
+		return float64(len([]rune(c))), nil
+	case nil:
+		return float64(0), nil
+	default:
+		return nil, fmt.Errorf("%s has no length", typeName(v))
+	}
+}
+
+func filterKeys(v interface{}) (interface{}, error) {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s has no keys", typeName(v))
+	}
+	keys := sortedKeys(obj)
+	out := make([]interface{}, len(keys))
+	for i, k := range keys {
+		out[i] = k
+	}
+	return out, nil
+}
+
+func sortedKeys(obj map[string]interface{}) []string {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func truthy(v interface{}) bool {
+	return v != nil && v != false
+}
+
+func compare(left interface{}, op string, right interface{}) bool {
+	if ln, ok := left.(float64); ok {
+		if rn, ok := right.(float64); ok {
+			switch op {
+			case "==":
+				return ln == rn
+			case "!=":
+				return ln != rn
+			case ">":
+				return ln > rn
+			case "<":
+				return ln < rn
+			case ">=":
+				return ln >= rn
+			case "<=":
+				return ln <= rn
+			}
+		}
+	}
+	if ls, ok := left.(string); ok {
+		if rs, ok := right.(string); ok {
+			switch op {
+			case ">":
+				return ls > rs
+			case "<":
+				return ls < rs
+			case ">=":
+				return ls >= rs
+			case "<=":
+				return ls <= rs
+			}
+		}
+	}
+	switch op {
+	case "==":
+		return reflect.DeepEqual(left, right)
+	case "!=":
+		return !reflect.DeepEqual(left, right)
+	}
+	return false
+}
+
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
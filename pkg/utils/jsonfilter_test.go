@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestFilterJSON(t *testing.T) {
+	var data interface{}
+	doc := `{"items": [
+		{"number": 1, "state": "open", "user": {"login": "alice"}, "draft": false},
+		{"number": 2, "state": "closed", "user": {"login": "bob"}, "draft": true},
+		{"number": 3, "state": "open", "user": {"login": "carol"}, "draft": true}
+	], "total": 3}`
+	if err := json.Unmarshal([]byte(doc), &data); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		expr string
+		want []interface{}
+	}{
+		{".total", []interface{}{3.0}},
+		{".items[0].user.login", []interface{}{"alice"}},
+		{".items[-1].number", []interface{}{3.0}},
+		{".items[].number", []interface{}{1.0, 2.0, 3.0}},
+		{`.items[] | select(.state == "open") | .user.login`, []interface{}{"alice", "carol"}},
+		{".items[] | select(.draft) | .number", []interface{}{2.0, 3.0}},
+		{".items[] | select(.number >= 2) | .number", []interface{}{2.0, 3.0}},
+		{".items | length", []interface{}{3.0}},
+		{`.["total"]`, []interface{}{3.0}},
+		{"keys", []interface{}{[]interface{}{"items", "total"}}},
+	}
+
+	for _, tt := range tests {
+		got, err := FilterJSON(tt.expr, data)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.expr, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.expr, tt.want, got)
+		}
+	}
+}
+
+func TestFilterJSONErrors(t *testing.T) {
+	for _, expr := range []string{"", ".items[", "select(.a ==)", ".total[]", "foo"} {
+		if _, err := FilterJSON(expr, map[string]interface{}{"total": 1.0}); err == nil {
+			t.Errorf("%q: expected error", expr)
+		}
+	}
+}
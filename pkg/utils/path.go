@@ -5,13 +5,53 @@ import (
 	"path/filepath"
 )
 
+// gk keeps its files in the XDG base directories, falling back to the
+// locations the XDG spec defines when the variables aren't set:
+//
+//	config  $XDG_CONFIG_HOME/gk  ~/.config/gk        config, profiles, workspaces
+//	themes  <config>/themes                           custom themes
+//	state   $XDG_STATE_HOME/gk   ~/.local/state/gk   inbox state
+
 // GetConfigDir returns the configuration directory path
 func GetConfigDir() (string, error) {
+	return xdgDir("XDG_CONFIG_HOME", ".config")
+}
+
+// GetThemesDir returns the directory custom themes are read from
+func GetThemesDir() (string, error) {
+	dir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "themes"), nil
+}
+
+// GetStateDir returns the directory for state that should persist between
+// runs but isn't configuration, such as what has been read in the inbox
+func GetStateDir() (string, error) {
+	return xdgDir("XDG_STATE_HOME", filepath.Join(".local", "state"))
+}
+
+// GetLegacyDir returns the directory older versions of gk used
+func GetLegacyDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".gk"), nil
+}
+
+// xdgDir returns the gk directory below an XDG base directory. Relative
+// paths in the variable are ignored, as the spec requires.
+func xdgDir(env, fallback string) (string, error) {
+	if base := os.Getenv(env); filepath.IsAbs(base) {
+		return filepath.Join(base, "gk"), nil
+	}
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(home, ".config", "gk"), nil
+	return filepath.Join(home, fallback, "gk"), nil
 }
 
 // EnsureDir ensures a directory exists, creating it if necessary